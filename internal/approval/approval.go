@@ -0,0 +1,99 @@
+package approval
+
+import (
+	"errors"
+	"time"
+
+	"kubectl-bot/internal/rbac"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	ErrDecided      = errors.New("this request has already been decided")
+	ErrExpired      = errors.New("this request has expired")
+	ErrSelfApproval = errors.New("you cannot approve your own request")
+)
+
+// NewRequest creates a pending approval request for an action that expires after ttl
+func NewRequest(requesterID int64, requesterName string, chatID int64, action Action, summary string, now time.Time, ttl time.Duration) *TelegramBotApproval {
+	return &TelegramBotApproval{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "kbot.go.mamad.dev/v1",
+			Kind:       "TelegramBotApproval",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "approval-",
+		},
+		Spec: TelegramBotApprovalSpec{
+			RequesterID:   requesterID,
+			RequesterName: requesterName,
+			ChatID:        chatID,
+			Action:        action,
+			Summary:       summary,
+			ExpiresAt:     metav1.NewTime(now.Add(ttl)),
+		},
+		Status: TelegramBotApprovalStatus{
+			Phase: PhasePending,
+		},
+	}
+}
+
+// Pending reports whether the request is still waiting for a decision
+func (a *TelegramBotApproval) Pending() bool {
+	return a.Status.Phase == PhasePending || a.Status.Phase == ""
+}
+
+// Expired reports whether the request can no longer be decided at now
+func (a *TelegramBotApproval) Expired(now time.Time) bool {
+	return !now.Before(a.Spec.ExpiresAt.Time)
+}
+
+// CheckDecision returns why userID may not approve (or reject) the request at now.
+// Whether the user holds the permission for the action is up to the caller.
+func (a *TelegramBotApproval) CheckDecision(userID int64, approve bool, now time.Time) error {
+	if !a.Pending() {
+		return ErrDecided
+	}
+	if a.Expired(now) {
+		return ErrExpired
+	}
+	if approve && userID == a.Spec.RequesterID {
+		return ErrSelfApproval
+	}
+	return nil
+}
+
+// Decide records the outcome of the request. userID is 0 for expiry.
+func (a *TelegramBotApproval) Decide(phase string, userID int64, userName string, now time.Time) {
+	decidedAt := metav1.NewTime(now)
+	a.Status.Phase = phase
+	a.Status.DecidedBy = userID
+	a.Status.DecidedByName = userName
+	a.Status.DecidedAt = &decidedAt
+}
+
+// PermissionVerb returns the permission verb the action requires.
+// Resuming a cronjob uses the same verb as suspending it.
+func (a Action) PermissionVerb() string {
+	if a.Command == "resume" {
+		return "suspend"
+	}
+	return a.Command
+}
+
+// PermissionCheck returns the check a user must pass to run the action.
+// An elevation is not about a single named object, so it has no resource name.
+func (a Action) PermissionCheck(userID int64) rbac.PermissionCheck {
+	check := rbac.PermissionCheck{
+		TelegramUserID: userID,
+		Namespace:      a.Namespace,
+		Resource:       a.Resource,
+		Verb:           a.PermissionVerb(),
+		ResourceName:   a.Name,
+	}
+	if a.Command == "elevate" {
+		check.ResourceName = ""
+	}
+	return check
+}
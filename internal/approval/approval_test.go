@@ -0,0 +1,93 @@
+package approval
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckDecision(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	action := Action{Command: "restart", Resource: "deployments", Namespace: "production", Name: "api"}
+
+	tests := []struct {
+		name     string
+		userID   int64
+		approve  bool
+		at       time.Time
+		decided  bool
+		expected error
+	}{
+		{"Another user approves", 2, true, now.Add(time.Minute), false, nil},
+		{"Requester approves own request", 1, true, now.Add(time.Minute), false, ErrSelfApproval},
+		{"Requester withdraws own request", 1, false, now.Add(time.Minute), false, nil},
+		{"Decided after expiry", 2, true, now.Add(30 * time.Minute), false, ErrExpired},
+		{"Already decided", 2, false, now.Add(time.Minute), true, ErrDecided},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := NewRequest(1, "alice", 100, action, "Restart api?", now, 30*time.Minute)
+			if tt.decided {
+				request.Decide(PhaseApproved, 3, "carol", now)
+			}
+
+			if err := request.CheckDecision(tt.userID, tt.approve, tt.at); err != tt.expected {
+				t.Errorf("CheckDecision() = %v, expected %v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecide(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	request := NewRequest(1, "alice", 100, Action{Command: "scale"}, "Scale?", now, time.Hour)
+
+	if !request.Pending() {
+		t.Fatal("New request should be pending")
+	}
+
+	request.Decide(PhaseRejected, 2, "bob", now)
+	if request.Pending() || request.Status.Phase != PhaseRejected {
+		t.Errorf("Phase = %s, expected %s", request.Status.Phase, PhaseRejected)
+	}
+	if request.Status.DecidedBy != 2 || !request.Status.DecidedAt.Time.Equal(now) {
+		t.Errorf("Decision not recorded: %+v", request.Status)
+	}
+}
+
+func TestActionPermissionCheck(t *testing.T) {
+	action := Action{Command: "resume", Resource: "cronjobs", Namespace: "production", Name: "backup"}
+
+	check := action.PermissionCheck(42)
+	if check.TelegramUserID != 42 || check.Namespace != "production" || check.Resource != "cronjobs" || check.ResourceName != "backup" {
+		t.Errorf("PermissionCheck() = %+v", check)
+	}
+	if check.Verb != "suspend" {
+		t.Errorf("Verb = %s, expected resume to need suspend", check.Verb)
+	}
+
+	action.Command = "scale"
+	if verb := action.PermissionVerb(); verb != "scale" {
+		t.Errorf("PermissionVerb() = %s, expected scale", verb)
+	}
+
+	elevate := Action{Command: "elevate", Resource: "*", Namespace: "production", Name: "admin", Duration: "1h0m0s"}
+	check = elevate.PermissionCheck(42)
+	if check.Verb != "elevate" || check.Resource != "*" || check.ResourceName != "" {
+		t.Errorf("PermissionCheck() for elevate = %+v", check)
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	original := NewRequest(1, "alice", 100, Action{Command: "restart"}, "Restart?", now, time.Hour)
+	original.Decide(PhaseApproved, 2, "bob", now)
+
+	copied := original.DeepCopy()
+	copied.Status.DecidedAt.Time = now.Add(time.Hour)
+	copied.Spec.Action.Name = "changed"
+
+	if !original.Status.DecidedAt.Time.Equal(now) || original.Spec.Action.Name != "" {
+		t.Error("DeepCopy should not share data with the original")
+	}
+}
@@ -0,0 +1,118 @@
+package approval
+
+import (
+	"context"
+	"fmt"
+
+	"kubectl-bot/internal/k8s"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Store keeps approval requests as TelegramBotApproval objects so they survive bot restarts
+type Store struct {
+	k8sClient *k8s.Client
+}
+
+// NewStore creates a store backed by the cluster
+func NewStore(k8sClient *k8s.Client) *Store {
+	return &Store{
+		k8sClient: k8sClient,
+	}
+}
+
+// Create stores a new approval request. The name is generated from GenerateName.
+func (s *Store) Create(ctx context.Context, approval *TelegramBotApproval) (*TelegramBotApproval, error) {
+	obj, err := toUnstructured(approval)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.k8sClient.GetDynamicClient().
+		Resource(k8s.TelegramBotApprovalGVR()).
+		Create(ctx, obj, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return fromUnstructured(created)
+}
+
+// Get retrieves an approval request by name
+func (s *Store) Get(ctx context.Context, name string) (*TelegramBotApproval, error) {
+	obj, err := s.k8sClient.GetDynamicClient().
+		Resource(k8s.TelegramBotApprovalGVR()).
+		Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return fromUnstructured(obj)
+}
+
+// Update stores changes to an approval request. It fails with a conflict error
+// if the request changed since it was read, so two users cannot both decide it.
+func (s *Store) Update(ctx context.Context, approval *TelegramBotApproval) (*TelegramBotApproval, error) {
+	obj, err := toUnstructured(approval)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.k8sClient.GetDynamicClient().
+		Resource(k8s.TelegramBotApprovalGVR()).
+		Update(ctx, obj, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return fromUnstructured(updated)
+}
+
+// List returns all approval requests
+func (s *Store) List(ctx context.Context) ([]TelegramBotApproval, error) {
+	list, err := s.k8sClient.GetDynamicClient().
+		Resource(k8s.TelegramBotApprovalGVR()).
+		List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	approvals := make([]TelegramBotApproval, 0, len(list.Items))
+	for i := range list.Items {
+		approval, err := fromUnstructured(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, *approval)
+	}
+
+	return approvals, nil
+}
+
+// Delete removes an approval request
+func (s *Store) Delete(ctx context.Context, name string) error {
+	return s.k8sClient.GetDynamicClient().
+		Resource(k8s.TelegramBotApprovalGVR()).
+		Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+func toUnstructured(approval *TelegramBotApproval) (*unstructured.Unstructured, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(approval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert to unstructured: %w", err)
+	}
+
+	result := &unstructured.Unstructured{Object: obj}
+	result.SetGroupVersionKind(k8s.TelegramBotApprovalGVR().GroupVersion().WithKind("TelegramBotApproval"))
+	return result, nil
+}
+
+func fromUnstructured(obj *unstructured.Unstructured) (*TelegramBotApproval, error) {
+	var approval TelegramBotApproval
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &approval); err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured to TelegramBotApproval: %w", err)
+	}
+	return &approval, nil
+}
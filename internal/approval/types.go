@@ -0,0 +1,131 @@
+package approval
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Approval phases
+const (
+	PhasePending  = "Pending"
+	PhaseApproved = "Approved"
+	PhaseRejected = "Rejected"
+	PhaseExpired  = "Expired"
+)
+
+// TelegramBotApproval is the Schema for the telegrambotapprovals API
+type TelegramBotApproval struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TelegramBotApprovalSpec   `json:"spec,omitempty"`
+	Status TelegramBotApprovalStatus `json:"status,omitempty"`
+}
+
+// TelegramBotApprovalSpec describes a change waiting for a second user's approval
+type TelegramBotApprovalSpec struct {
+	RequesterID   int64       `json:"requesterId"`
+	RequesterName string      `json:"requesterName,omitempty"`
+	ChatID        int64       `json:"chatId"`
+	MessageID     int         `json:"messageId,omitempty"`
+	Action        Action      `json:"action"`
+	Summary       string      `json:"summary"`
+	ExpiresAt     metav1.Time `json:"expiresAt"`
+}
+
+// Action is a mutating command stored so it can run after approval.
+// Command is restart, rollback, scale, trigger, suspend, resume or elevate.
+// For elevate, Name is the elevation target and Resource the resource it covers.
+type Action struct {
+	Command   string `json:"command"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Replicas  int32  `json:"replicas,omitempty"`
+	Revision  int64  `json:"revision,omitempty"`
+	Duration  string `json:"duration,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// TelegramBotApprovalStatus records the decision on an approval request
+type TelegramBotApprovalStatus struct {
+	Phase         string       `json:"phase,omitempty"`
+	DecidedBy     int64        `json:"decidedBy,omitempty"`
+	DecidedByName string       `json:"decidedByName,omitempty"`
+	DecidedAt     *metav1.Time `json:"decidedAt,omitempty"`
+	Result        string       `json:"result,omitempty"`
+}
+
+// TelegramBotApprovalList contains a list of TelegramBotApproval
+type TelegramBotApprovalList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TelegramBotApproval `json:"items"`
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *TelegramBotApproval) DeepCopyInto(out *TelegramBotApproval) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy creates a deep copy
+func (in *TelegramBotApproval) DeepCopy() *TelegramBotApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(TelegramBotApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject creates a deep copy object
+func (in *TelegramBotApproval) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies spec
+func (in *TelegramBotApprovalSpec) DeepCopyInto(out *TelegramBotApprovalSpec) {
+	*out = *in
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+}
+
+// DeepCopyInto copies status
+func (in *TelegramBotApprovalStatus) DeepCopyInto(out *TelegramBotApprovalStatus) {
+	*out = *in
+	if in.DecidedAt != nil {
+		out.DecidedAt = in.DecidedAt.DeepCopy()
+	}
+}
+
+// DeepCopyInto copies list
+func (in *TelegramBotApprovalList) DeepCopyInto(out *TelegramBotApprovalList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TelegramBotApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy creates a deep copy of list
+func (in *TelegramBotApprovalList) DeepCopy() *TelegramBotApprovalList {
+	if in == nil {
+		return nil
+	}
+	out := new(TelegramBotApprovalList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject creates a deep copy object of list
+func (in *TelegramBotApprovalList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
@@ -0,0 +1,285 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"kubectl-bot/internal/approval"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+const (
+	// approvalCallbackPrefix marks the buttons of approval requests. Unlike other
+	// buttons they name a stored request, so they keep working after a restart.
+	approvalCallbackPrefix = "approval:"
+
+	// approvalSweepInterval is how often expired approval requests are closed
+	approvalSweepInterval = time.Minute
+
+	// approvalRetention is how long decided approval requests are kept for auditing
+	approvalRetention = 7 * 24 * time.Hour
+)
+
+// requestApproval stores an action as a pending approval request and posts it
+// with Approve and Reject buttons. A request made in a private chat is also sent
+// to the admins, since nobody else could see its buttons.
+func (b *Bot) requestApproval(ctx context.Context, chatID int64, user *tgbotapi.User, action approval.Action, summary string) {
+	request := approval.NewRequest(user.ID, user.String(), chatID, action, summary, time.Now(), b.config.ApprovalTTL)
+
+	request, err := b.approvals.Create(ctx, request)
+	if err != nil {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Error: failed to create approval request: %v", err))
+		return
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Approve", approvalCallbackData("approve", request.Name)),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Reject", approvalCallbackData("reject", request.Name)),
+		),
+	)
+
+	text := formatApprovalRequest(request, b.config.ApprovalTTL)
+	messageID := b.sendMessageWithKeyboard(chatID, text, keyboard)
+	if messageID == 0 {
+		// Nobody can decide a request whose message was never posted
+		if err := b.approvals.Delete(ctx, request.Name); err != nil {
+			log.Printf("Failed to delete unposted approval request %s: %v", request.Name, err)
+		}
+		b.sendMessage(chatID, "❌ Error: failed to post the approval request, nothing was changed")
+		return
+	}
+
+	// Remember the message so its buttons can be removed once the request expires
+	request.Spec.MessageID = messageID
+	if _, err := b.approvals.Update(ctx, request); err != nil {
+		log.Printf("Failed to store message of approval request %s: %v", request.Name, err)
+	}
+
+	for _, id := range approvalCopyRecipients(b.adminIDs(ctx), user.ID, chatID) {
+		b.sendMessageWithKeyboard(id, text, keyboard)
+	}
+}
+
+// approvalCopyRecipients returns the admins who get their own copy of an approval
+// request: all admins but the requester when the request was made in the
+// requester's private chat, and nobody when it was made in a group
+func approvalCopyRecipients(admins []int64, requesterID, chatID int64) []int64 {
+	// A user's private chat has the same ID as the user
+	if chatID != requesterID {
+		return nil
+	}
+
+	recipients := []int64{}
+	for _, id := range admins {
+		if id != requesterID {
+			recipients = append(recipients, id)
+		}
+	}
+	sort.Slice(recipients, func(i, j int) bool { return recipients[i] < recipients[j] })
+	return recipients
+}
+
+// editApprovalMessages shows text in the message whose button was pressed and,
+// if that was a copy sent to an admin, in the original request message as well
+func (b *Bot) editApprovalMessages(query *tgbotapi.CallbackQuery, request *approval.TelegramBotApproval, text string) {
+	b.editMessage(query.Message.Chat.ID, query.Message.MessageID, text, nil)
+
+	original := request.Spec.ChatID == query.Message.Chat.ID && request.Spec.MessageID == query.Message.MessageID
+	if !original && request.Spec.MessageID != 0 {
+		b.editMessage(request.Spec.ChatID, request.Spec.MessageID, text, nil)
+	}
+}
+
+// handleApprovalCallback processes Approve and Reject button presses
+func (b *Bot) handleApprovalCallback(ctx context.Context, query *tgbotapi.CallbackQuery) {
+	decision, name, ok := parseApprovalCallback(query.Data)
+	if !ok {
+		b.answerCallback(query.ID, "Unknown button")
+		return
+	}
+
+	request, err := b.approvals.Get(ctx, name)
+	if err != nil {
+		b.answerCallback(query.ID, "This request no longer exists")
+		return
+	}
+
+	userID := query.From.ID
+	approve := decision == "approve"
+	now := time.Now()
+
+	if err := request.CheckDecision(userID, approve, now); err != nil {
+		if err == approval.ErrExpired {
+			b.expireApproval(ctx, request)
+		}
+		b.answerCallback(query.ID, err.Error())
+		return
+	}
+
+	// The requester may withdraw their own request; anyone else deciding it
+	// must be allowed to perform the action themselves. Elevations are decided by admins.
+	if userID != request.Spec.RequesterID && request.Spec.Action.Command == "elevate" {
+		if !b.isAdmin(ctx, userID) {
+			b.answerCallback(query.ID, "❌ Only admins can approve elevations")
+			return
+		}
+	} else if userID != request.Spec.RequesterID {
+		allowed, reason, err := b.validator.CheckPermission(ctx, request.Spec.Action.PermissionCheck(userID))
+		if err != nil || !allowed {
+			if reason == "" {
+				reason = "you are not allowed to perform this action"
+			}
+			b.answerCallback(query.ID, "❌ "+reason)
+			return
+		}
+	}
+
+	phase := approval.PhaseRejected
+	if approve {
+		phase = approval.PhaseApproved
+	}
+	request.Decide(phase, userID, query.From.String(), now)
+
+	// Updating with the stored resource version fails if someone else decided first
+	request, err = b.approvals.Update(ctx, request)
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			b.answerCallback(query.ID, approval.ErrDecided.Error())
+			return
+		}
+		b.answerCallback(query.ID, fmt.Sprintf("Failed to record decision: %v", err))
+		return
+	}
+
+	b.answerCallback(query.ID, "")
+
+	if !approve {
+		outcome := fmt.Sprintf("❌ Rejected by `%s`", request.Status.DecidedByName)
+		if userID == request.Spec.RequesterID {
+			outcome = "🚫 Withdrawn by the requester"
+		}
+		b.editApprovalMessages(query, request, request.Spec.Summary+"\n\n"+outcome)
+		return
+	}
+
+	approved := fmt.Sprintf("%s\n\n👥 Requested by `%s`, approved by `%s`",
+		request.Spec.Summary, request.Spec.RequesterName, request.Status.DecidedByName)
+	b.editApprovalMessages(query, request, approved+"\n⏳ Running...")
+
+	// The requester must still hold the permission when the action runs
+	result := ""
+	allowed, reason, err := b.validator.CheckPermission(ctx, request.Spec.Action.PermissionCheck(request.Spec.RequesterID))
+	if err != nil || !allowed {
+		result = fmt.Sprintf("❌ The requester is no longer allowed to perform this action: %s", reason)
+	} else if request.Spec.Action.Command == "elevate" {
+		result = b.applyElevation(ctx, request.Spec.RequesterID, request.Spec.RequesterName, request.Spec.Action, userID)
+	} else {
+		result = b.runAction(ctx, request.Spec.Action)
+	}
+
+	request.Status.Result = result
+	if _, err := b.approvals.Update(ctx, request); err != nil {
+		log.Printf("Failed to store result of approval request %s: %v", request.Name, err)
+	}
+
+	b.editApprovalMessages(query, request, approved+"\n"+result)
+}
+
+// expireApproval closes a pending request whose time ran out
+func (b *Bot) expireApproval(ctx context.Context, request *approval.TelegramBotApproval) {
+	request.Decide(approval.PhaseExpired, 0, "", time.Now())
+	if _, err := b.approvals.Update(ctx, request); err != nil {
+		log.Printf("Failed to expire approval request %s: %v", request.Name, err)
+		return
+	}
+
+	if request.Spec.MessageID != 0 {
+		b.editMessage(request.Spec.ChatID, request.Spec.MessageID,
+			request.Spec.Summary+"\n\n⌛ Approval request expired, nothing was changed", nil)
+	}
+}
+
+// runApprovalSweeper periodically expires pending requests and deletes old decided ones
+func (b *Bot) runApprovalSweeper(ctx context.Context) {
+	ticker := time.NewTicker(approvalSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		b.sweepApprovals(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweepApprovals runs one pass of the approval sweeper
+func (b *Bot) sweepApprovals(ctx context.Context) {
+	requests, err := b.approvals.List(ctx)
+	if err != nil {
+		log.Printf("Failed to list approval requests: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for i := range requests {
+		request := &requests[i]
+
+		if request.Pending() {
+			if request.Expired(now) {
+				b.expireApproval(ctx, request)
+			}
+			continue
+		}
+
+		if request.Status.DecidedAt != nil && now.Sub(request.Status.DecidedAt.Time) > approvalRetention {
+			if err := b.approvals.Delete(ctx, request.Name); err != nil {
+				log.Printf("Failed to delete approval request %s: %v", request.Name, err)
+			}
+		}
+	}
+}
+
+// formatApprovalRequest renders the message of a pending approval request
+func formatApprovalRequest(request *approval.TelegramBotApproval, ttl time.Duration) string {
+	if request.Spec.Action.Command == "elevate" {
+		return fmt.Sprintf("%s\n\n🛡 Elevations into namespace *%s* need an admin's approval.\n"+
+			"Requested by `%s`, expires in %s.",
+			request.Spec.Summary, request.Spec.Action.Namespace, request.Spec.RequesterName, duration.HumanDuration(ttl))
+	}
+
+	return fmt.Sprintf("%s\n\n🛡 Namespace *%s* is protected: another user allowed to %s %s must approve.\n"+
+		"Requested by `%s`, expires in %s.",
+		request.Spec.Summary, request.Spec.Action.Namespace, request.Spec.Action.PermissionVerb(),
+		request.Spec.Action.Resource, request.Spec.RequesterName, duration.HumanDuration(ttl))
+}
+
+// approvalCallbackData builds the callback data of an approval button
+func approvalCallbackData(decision, name string) string {
+	return approvalCallbackPrefix + decision + ":" + name
+}
+
+// parseApprovalCallback splits approval callback data into decision and request name
+func parseApprovalCallback(data string) (string, string, bool) {
+	rest, found := strings.CutPrefix(data, approvalCallbackPrefix)
+	if !found {
+		return "", "", false
+	}
+
+	decision, name, found := strings.Cut(rest, ":")
+	if !found || name == "" || (decision != "approve" && decision != "reject") {
+		return "", "", false
+	}
+
+	return decision, name, true
+}
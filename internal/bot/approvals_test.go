@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"kubectl-bot/internal/approval"
+)
+
+func TestApprovalCallbackData(t *testing.T) {
+	data := approvalCallbackData("approve", "approval-x7k2p")
+	if len(data) > 64 {
+		t.Errorf("Callback data %q exceeds Telegram's 64 byte limit", data)
+	}
+
+	decision, name, ok := parseApprovalCallback(data)
+	if !ok || decision != "approve" || name != "approval-x7k2p" {
+		t.Errorf("parseApprovalCallback(%q) = %q, %q, %v", data, decision, name, ok)
+	}
+
+	for _, invalid := range []string{"", "0123456789abcdef", "approval:", "approval:approve:", "approval:delete:approval-1"} {
+		if _, _, ok := parseApprovalCallback(invalid); ok {
+			t.Errorf("parseApprovalCallback(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestApprovalCopyRecipients(t *testing.T) {
+	admins := []int64{30, 10, 20}
+
+	// Requests from a private chat go to every other admin
+	recipients := approvalCopyRecipients(admins, 20, 20)
+	if !reflect.DeepEqual(recipients, []int64{10, 30}) {
+		t.Errorf("approvalCopyRecipients() = %v, expected [10 30]", recipients)
+	}
+
+	// Everyone in a group already sees the request
+	if recipients := approvalCopyRecipients(admins, 20, -1001234); len(recipients) != 0 {
+		t.Errorf("approvalCopyRecipients() = %v, expected no copies for a group", recipients)
+	}
+}
+
+func TestFormatApprovalRequest(t *testing.T) {
+	action := approval.Action{Command: "resume", Resource: "cronjobs", Namespace: "production", Name: "backup"}
+	request := approval.NewRequest(1, "alice", 100, action, "Resume backup?", time.Now(), 30*time.Minute)
+
+	text := formatApprovalRequest(request, 30*time.Minute)
+	for _, expected := range []string{"Resume backup?", "*production* is protected", "allowed to suspend cronjobs", "`alice`", "expires in 30m"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("formatApprovalRequest() = %q, expected it to contain %q", text, expected)
+		}
+	}
+}
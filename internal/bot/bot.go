@@ -5,6 +5,7 @@ import (
 	"log"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"kubectl-bot/internal/approval"
 	"kubectl-bot/internal/config"
 	"kubectl-bot/internal/k8s"
 	"kubectl-bot/internal/rbac"
@@ -16,6 +17,10 @@ type Bot struct {
 	rbac      *rbac.Manager
 	validator *rbac.Validator
 	config    *config.Config
+	callbacks *callbackRegistry
+	follows   *followLimiter
+	redactor  *redactor
+	approvals *approval.Store
 }
 
 // NewBot creates a new Telegram bot
@@ -27,6 +32,11 @@ func NewBot(cfg *config.Config, k8sClient *k8s.Client) (*Bot, error) {
 
 	log.Printf("Authorized on account %s", api.Self.UserName)
 
+	redactor, err := newRedactor(cfg.RedactionPatterns)
+	if err != nil {
+		return nil, err
+	}
+
 	rbacManager := rbac.NewManager(k8sClient, cfg)
 	validator := rbac.NewValidator(rbacManager, k8sClient)
 
@@ -36,6 +46,10 @@ func NewBot(cfg *config.Config, k8sClient *k8s.Client) (*Bot, error) {
 		rbac:      rbacManager,
 		validator: validator,
 		config:    cfg,
+		callbacks: newCallbackRegistry(),
+		follows:   newFollowLimiter(),
+		redactor:  redactor,
+		approvals: approval.NewStore(k8sClient),
 	}, nil
 }
 
@@ -46,6 +60,21 @@ func (b *Bot) Start(ctx context.Context) error {
 		log.Printf("Warning: Failed to set bot commands: %v", err)
 	}
 
+	// Older versions gave every user the viewer role, whose defaults now widen their access
+	if cleared, err := b.rbac.MigrateViewerRoles(ctx); err != nil {
+		log.Printf("Warning: Failed to migrate viewer roles: %v", err)
+	} else if len(cleared) > 0 {
+		log.Printf("Removed the viewer role older versions gave users %v", cleared)
+	}
+
+	// Remove time-bound permissions and elevations once they expire
+	go b.runGrantSweeper(ctx)
+
+	// Close approval requests that expire, including those left over from before a restart
+	if len(b.config.ProtectedNamespaces) > 0 || len(b.config.ElevationApprovalNamespaces) > 0 {
+		go b.runApprovalSweeper(ctx)
+	}
+
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 
@@ -60,6 +89,11 @@ func (b *Bot) Start(ctx context.Context) error {
 			b.api.StopReceivingUpdates()
 			return nil
 		case update := <-updates:
+			if update.CallbackQuery != nil {
+				go b.handleCallback(ctx, update.CallbackQuery)
+				continue
+			}
+
 			if update.Message == nil {
 				continue
 			}
@@ -102,14 +136,35 @@ func (b *Bot) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 		b.handleStart(ctx, message)
 	case "help":
 		b.handleHelp(ctx, message)
+	case "whoami":
+		b.handleWhoAmI(ctx, message)
+	case "can_i", "can":
+		args, ok := canIArgs(message)
+		if !ok {
+			b.sendMessage(message.Chat.ID, "Unknown command. Type /help for available commands.")
+			return
+		}
+		b.handleCanI(ctx, message, args)
 	case "namespaces":
 		b.handleNamespaces(ctx, message)
 	case "pods":
 		b.handlePods(ctx, message)
 	case "deployments":
 		b.handleDeployments(ctx, message)
+	case "statefulsets":
+		b.handleStatefulSets(ctx, message)
+	case "daemonsets":
+		b.handleDaemonSets(ctx, message)
+	case "cronjobs":
+		b.handleCronJobs(ctx, message)
+	case "jobs":
+		b.handleJobs(ctx, message)
+	case "events":
+		b.handleEvents(ctx, message)
 	case "services":
 		b.handleServices(ctx, message)
+	case "service":
+		b.handleService(ctx, message)
 	case "logs":
 		b.handleLogs(ctx, message)
 	case "restart":
@@ -118,10 +173,22 @@ func (b *Bot) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 		b.handleRollback(ctx, message)
 	case "scale":
 		b.handleScale(ctx, message)
+	case "trigger":
+		b.handleTrigger(ctx, message)
+	case "suspend":
+		b.handleSuspend(ctx, message, true)
+	case "resume":
+		b.handleSuspend(ctx, message, false)
+	case "elevate":
+		b.handleElevate(ctx, message)
 	case "grant":
 		b.handleGrant(ctx, message)
 	case "revoke":
 		b.handleRevoke(ctx, message)
+	case "setrole":
+		b.handleSetRole(ctx, message)
+	case "team":
+		b.handleTeam(ctx, message)
 	case "permissions":
 		b.handlePermissions(ctx, message)
 	case "selfupdate":
@@ -131,16 +198,6 @@ func (b *Bot) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 	}
 }
 
-// sendMessage sends a text message to a chat
-func (b *Bot) sendMessage(chatID int64, text string) {
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ParseMode = "Markdown"
-	_, err := b.api.Send(msg)
-	if err != nil {
-		log.Printf("Failed to send message: %v", err)
-	}
-}
-
 // getUserRole returns the user's role for display
 func (b *Bot) getUserRole(ctx context.Context, userID int64) string {
 	if b.rbac.IsBootstrapAdmin(userID) {
@@ -149,27 +206,35 @@ func (b *Bot) getUserRole(ctx context.Context, userID int64) string {
 
 	permission, err := b.rbac.GetUserPermission(ctx, userID)
 	if err != nil {
+		// Users without permissions of their own may still have access through teams
+		if teams, _ := b.rbac.GetUserTeams(ctx, userID); len(teams) > 0 {
+			return "team member"
+		}
 		return "none"
 	}
 
+	if permission.Spec.Role == "" {
+		return "none"
+	}
 	return permission.Spec.Role
 }
 
-// hasAnyPermission checks if user has any permissions (bootstrap admin or CRD permissions)
+// hasAnyPermission checks if user has any permissions (bootstrap admin, role, CRD or team permissions)
 func (b *Bot) hasAnyPermission(ctx context.Context, userID int64) bool {
 	// Check bootstrap admin
 	if b.rbac.IsBootstrapAdmin(userID) {
 		return true
 	}
 
-	// Check CRD permissions
-	permission, err := b.rbac.GetUserPermission(ctx, userID)
+	// Check CRD permissions, including those of the user's teams
+	access, err := b.rbac.GetAccess(ctx, userID)
 	if err != nil {
 		return false
 	}
 
-	// User has permissions if role is set and has at least one permission entry
-	return permission.Spec.Role != "" && len(permission.Spec.Permissions) > 0
+	// User has permissions if they have a role (admins need no entries) or at least
+	// one permission entry of their own or of a team
+	return !access.IsEmpty()
 }
 
 // setupCommands sets up bot commands for Telegram UI
@@ -177,16 +242,30 @@ func (b *Bot) setupCommands() error {
 	commands := []tgbotapi.BotCommand{
 		{Command: "start", Description: "Start the bot and check your permissions"},
 		{Command: "help", Description: "Show help and available commands"},
+		{Command: "whoami", Description: "Show your role, namespaces and granted verbs"},
+		{Command: "can_i", Description: "Check a permission and what decides it"},
 		{Command: "namespaces", Description: "List accessible namespaces"},
 		{Command: "pods", Description: "List pods in a namespace"},
 		{Command: "deployments", Description: "List deployments in a namespace"},
+		{Command: "statefulsets", Description: "List statefulsets in a namespace"},
+		{Command: "daemonsets", Description: "List daemonsets in a namespace"},
+		{Command: "cronjobs", Description: "List cronjobs in a namespace"},
+		{Command: "jobs", Description: "List jobs in a namespace"},
+		{Command: "events", Description: "List cluster events in a namespace"},
 		{Command: "services", Description: "List services in a namespace"},
+		{Command: "service", Description: "Show service details"},
 		{Command: "logs", Description: "Get pod logs"},
-		{Command: "restart", Description: "Restart a deployment"},
+		{Command: "restart", Description: "Restart a deployment, statefulset or daemonset"},
 		{Command: "rollback", Description: "Rollback a deployment"},
-		{Command: "scale", Description: "Scale a deployment"},
+		{Command: "scale", Description: "Scale a deployment or statefulset"},
+		{Command: "trigger", Description: "Run a cronjob now"},
+		{Command: "suspend", Description: "Suspend a cronjob"},
+		{Command: "resume", Description: "Resume a cronjob"},
+		{Command: "elevate", Description: "Temporarily elevate your own access with a reason"},
 		{Command: "grant", Description: "Grant permissions to a user (admin only)"},
 		{Command: "revoke", Description: "Revoke permissions from a user (admin only)"},
+		{Command: "setrole", Description: "Set a user's role (admin only)"},
+		{Command: "team", Description: "Manage teams and their members (admin only)"},
 		{Command: "permissions", Description: "View user permissions"},
 		{Command: "selfupdate", Description: "Update bot to latest image (admin only)"},
 	}
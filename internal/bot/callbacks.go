@@ -0,0 +1,128 @@
+package bot
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var (
+	errCallbackExpired  = errors.New("this button has expired")
+	errCallbackNotOwner = errors.New("only the user who issued the command can use this button")
+)
+
+// callbackFunc runs when an inline keyboard button is pressed
+type callbackFunc func(ctx context.Context, query *tgbotapi.CallbackQuery)
+
+// callbackAction is an action bound to an inline keyboard button
+type callbackAction struct {
+	userID  int64
+	expires time.Time
+	run     callbackFunc
+}
+
+// callbackRegistry stores inline keyboard actions keyed by their callback data.
+// Telegram limits callback data to 64 bytes, so buttons carry a random ID
+// instead of the command arguments.
+type callbackRegistry struct {
+	mu      sync.Mutex
+	actions map[string]callbackAction
+}
+
+// newCallbackRegistry creates an empty callback registry
+func newCallbackRegistry() *callbackRegistry {
+	return &callbackRegistry{
+		actions: make(map[string]callbackAction),
+	}
+}
+
+// register stores an action usable only by userID until ttl elapses and returns its callback data
+func (r *callbackRegistry) register(userID int64, ttl time.Duration, run callbackFunc) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Drop expired actions
+	now := time.Now()
+	for id, action := range r.actions {
+		if now.After(action.expires) {
+			delete(r.actions, id)
+		}
+	}
+
+	id := newCallbackID()
+	r.actions[id] = callbackAction{
+		userID:  userID,
+		expires: now.Add(ttl),
+		run:     run,
+	}
+	return id
+}
+
+// get returns the action for a callback if it exists, has not expired and belongs to userID
+func (r *callbackRegistry) get(id string, userID int64) (callbackFunc, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	action, ok := r.actions[id]
+	if !ok || time.Now().After(action.expires) {
+		delete(r.actions, id)
+		return nil, errCallbackExpired
+	}
+	if action.userID != userID {
+		return nil, errCallbackNotOwner
+	}
+
+	return action.run, nil
+}
+
+// remove deletes actions so their buttons can no longer be used
+func (r *callbackRegistry) remove(ids ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range ids {
+		delete(r.actions, id)
+	}
+}
+
+// newCallbackID returns a random hex identifier for callback data
+func newCallbackID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		log.Printf("Failed to generate callback ID: %v", err)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// handleCallback processes inline keyboard button presses
+func (b *Bot) handleCallback(ctx context.Context, query *tgbotapi.CallbackQuery) {
+	log.Printf("Received callback from user %d: %s", query.From.ID, query.Data)
+
+	if strings.HasPrefix(query.Data, approvalCallbackPrefix) {
+		b.handleApprovalCallback(ctx, query)
+		return
+	}
+
+	run, err := b.callbacks.get(query.Data, query.From.ID)
+	if err != nil {
+		b.answerCallback(query.ID, err.Error())
+		return
+	}
+
+	b.answerCallback(query.ID, "")
+	run(ctx, query)
+}
+
+// answerCallback acknowledges a callback query, optionally showing a notification
+func (b *Bot) answerCallback(queryID, text string) {
+	if _, err := b.api.Request(tgbotapi.NewCallback(queryID, text)); err != nil {
+		log.Printf("Failed to answer callback: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestCallbackRegistry(t *testing.T) {
+	registry := newCallbackRegistry()
+	noop := func(ctx context.Context, query *tgbotapi.CallbackQuery) {}
+
+	id := registry.register(123, time.Minute, noop)
+	if len(id) == 0 || len(id) > 64 {
+		t.Fatalf("Callback ID %q should be non-empty and fit Telegram's 64 byte limit", id)
+	}
+
+	if _, err := registry.get(id, 123); err != nil {
+		t.Errorf("Owner should be able to use callback, got error: %v", err)
+	}
+
+	if _, err := registry.get(id, 456); err != errCallbackNotOwner {
+		t.Errorf("Other user should get errCallbackNotOwner, got: %v", err)
+	}
+
+	registry.remove(id)
+	if _, err := registry.get(id, 123); err != errCallbackExpired {
+		t.Errorf("Removed callback should be expired, got: %v", err)
+	}
+
+	expired := registry.register(123, -time.Second, noop)
+	if _, err := registry.get(expired, 123); err != errCallbackExpired {
+		t.Errorf("Callback past its TTL should be expired, got: %v", err)
+	}
+}
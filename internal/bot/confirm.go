@@ -0,0 +1,192 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"kubectl-bot/internal/approval"
+	"kubectl-bot/internal/k8s"
+	"kubectl-bot/internal/rbac"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// confirmationTTL is how long the buttons of a confirmation stay usable
+const confirmationTTL = 2 * time.Minute
+
+// confirmAction performs a confirmed operation and returns the reply to show
+type confirmAction func(ctx context.Context) string
+
+// requestConfirmation shows summary with Confirm and Cancel buttons and runs action
+// once the issuing user confirms. The buttons expire after confirmationTTL.
+func (b *Bot) requestConfirmation(chatID, userID int64, summary string, action confirmAction) {
+	// The first of confirm, cancel and expiry wins; the others become no-ops
+	var once sync.Once
+	var confirmID, cancelID string
+	finish := func() bool {
+		finished := false
+		once.Do(func() {
+			b.callbacks.remove(confirmID, cancelID)
+			finished = true
+		})
+		return finished
+	}
+
+	confirmID = b.callbacks.register(userID, confirmationTTL, func(ctx context.Context, query *tgbotapi.CallbackQuery) {
+		if !finish() {
+			return
+		}
+		messageID := query.Message.MessageID
+		b.editMessage(chatID, messageID, summary+"\n\n⏳ Running...", nil)
+		b.editMessage(chatID, messageID, summary+"\n\n"+action(ctx), nil)
+	})
+	cancelID = b.callbacks.register(userID, confirmationTTL, func(ctx context.Context, query *tgbotapi.CallbackQuery) {
+		if finish() {
+			b.editMessage(chatID, query.Message.MessageID, summary+"\n\n🚫 Cancelled", nil)
+		}
+	})
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Confirm", confirmID),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Cancel", cancelID),
+		),
+	)
+
+	messageID := b.sendMessageWithKeyboard(chatID, summary, keyboard)
+	if messageID == 0 {
+		finish()
+		return
+	}
+
+	time.AfterFunc(confirmationTTL, func() {
+		if finish() {
+			b.editMessage(chatID, messageID, summary+"\n\n⌛ Confirmation expired, nothing was changed", nil)
+		}
+	})
+}
+
+// performAction runs a mutating action the user is permitted to perform. In
+// protected namespaces it becomes an approval request for a second user;
+// elsewhere it asks for confirmation first when confirm is set.
+func (b *Bot) performAction(ctx context.Context, chatID int64, user *tgbotapi.User, action approval.Action, summary string, confirm bool) {
+	if b.config.IsProtectedNamespace(action.Namespace) {
+		b.requestApproval(ctx, chatID, user, action, summary)
+		return
+	}
+
+	if !confirm {
+		b.sendMessage(chatID, b.runAction(ctx, action))
+		return
+	}
+
+	b.requestConfirmation(chatID, user.ID, summary, func(ctx context.Context) string {
+		// Permissions may have changed while waiting for confirmation
+		if allowed, reason, err := b.validator.CheckPermission(ctx, action.PermissionCheck(user.ID)); err != nil || !allowed {
+			return rbac.FormatPermissionDenied(reason)
+		}
+		return b.runAction(ctx, action)
+	})
+}
+
+// runAction executes a mutating action and returns the reply to show
+func (b *Bot) runAction(ctx context.Context, action approval.Action) string {
+	namespace, name := action.Namespace, action.Name
+
+	switch action.Command {
+	case "restart":
+		var err error
+		switch action.Resource {
+		case "statefulsets":
+			err = b.k8sClient.RestartStatefulSet(ctx, namespace, name)
+		case "daemonsets":
+			err = b.k8sClient.RestartDaemonSet(ctx, namespace, name)
+		default:
+			err = b.k8sClient.RestartDeployment(ctx, namespace, name)
+		}
+		if err != nil {
+			return fmt.Sprintf("❌ Error: %v", err)
+		}
+		return fmt.Sprintf("✅ %s `%s` restarted in namespace *%s*", workloadKinds[action.Resource], name, namespace)
+
+	case "rollback":
+		// Roll back to the revision that was shown, even if a new rollout happened meanwhile
+		if err := b.k8sClient.RollbackDeployment(ctx, namespace, name, action.Revision); err != nil {
+			return fmt.Sprintf("❌ Error: %v", err)
+		}
+		return fmt.Sprintf("✅ Deployment `%s` rolled back to revision %d in namespace *%s*", name, action.Revision, namespace)
+
+	case "scale":
+		var err error
+		switch action.Resource {
+		case "statefulsets":
+			err = b.k8sClient.ScaleStatefulSet(ctx, namespace, name, action.Replicas)
+		default:
+			err = b.k8sClient.ScaleDeployment(ctx, namespace, name, action.Replicas)
+		}
+		if err != nil {
+			return fmt.Sprintf("❌ Error: %v", err)
+		}
+		return fmt.Sprintf("✅ %s `%s` scaled to %d replicas in namespace *%s*",
+			workloadKinds[action.Resource], name, action.Replicas, namespace)
+
+	case "trigger":
+		job, err := b.k8sClient.TriggerCronJob(ctx, namespace, name)
+		if err != nil {
+			return fmt.Sprintf("❌ Error: %v", err)
+		}
+		return fmt.Sprintf("✅ Job `%s` created from cronjob `%s` in namespace *%s*", job.Name, name, namespace)
+
+	case "suspend", "resume":
+		suspend := action.Command == "suspend"
+		if err := b.k8sClient.SuspendCronJob(ctx, namespace, name, suspend); err != nil {
+			return fmt.Sprintf("❌ Error: %v", err)
+		}
+
+		state := "resumed"
+		if suspend {
+			state = "suspended"
+		}
+		return fmt.Sprintf("✅ CronJob `%s` %s in namespace *%s*", name, state, namespace)
+
+	default:
+		return fmt.Sprintf("❌ Unsupported action: %s", action.Command)
+	}
+}
+
+// restartSummary describes a pending workload restart
+func restartSummary(resource, name, namespace string, replicas int32) string {
+	return fmt.Sprintf("🔄 *Restart %s `%s`* in namespace *%s*?\n\nAll %d pods will be replaced by a rolling restart.",
+		workloadKinds[resource], name, namespace, replicas)
+}
+
+// scaleSummary describes a pending scale operation
+func scaleSummary(resource, name, namespace string, current, target int32) string {
+	return fmt.Sprintf("📏 *Scale %s `%s`* in namespace *%s*?\n\nReplicas: %d → %d",
+		workloadKinds[resource], name, namespace, current, target)
+}
+
+// rollbackSummary describes a pending rollback from current to target
+func rollbackSummary(name, namespace string, current, target *k8s.DeploymentRevision) string {
+	summary := fmt.Sprintf("⏪ *Roll back Deployment `%s`* in namespace *%s*?\n\n", name, namespace)
+	if current != nil {
+		summary += fmt.Sprintf("Current: revision %d (`%s`)\n", current.Number, strings.Join(current.Images, "`, `"))
+	}
+	summary += fmt.Sprintf("Target: revision %d (`%s`)", target.Number, strings.Join(target.Images, "`, `"))
+	return summary
+}
+
+// cronJobSummary describes a pending trigger, suspend or resume of a cronjob
+func cronJobSummary(command, name, namespace string) string {
+	switch command {
+	case "trigger":
+		return fmt.Sprintf("▶️ *Trigger CronJob `%s`* in namespace *%s*?\n\nA new job will be created from it now.", name, namespace)
+	case "suspend":
+		return fmt.Sprintf("⏸ *Suspend CronJob `%s`* in namespace *%s*?\n\nNo new jobs will be scheduled until it is resumed.", name, namespace)
+	default:
+		return fmt.Sprintf("▶️ *Resume CronJob `%s`* in namespace *%s*?\n\nJobs will be scheduled again.", name, namespace)
+	}
+}
@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"kubectl-bot/internal/k8s"
+)
+
+func TestScaleSummary(t *testing.T) {
+	summary := scaleSummary("statefulsets", "db", "prod", 3, 5)
+
+	for _, expected := range []string{"StatefulSet `db`", "*prod*", "3 → 5"} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("scaleSummary() = %q, expected it to contain %q", summary, expected)
+		}
+	}
+}
+
+func TestRestartSummary(t *testing.T) {
+	summary := restartSummary("deployments", "api", "staging", 4)
+
+	for _, expected := range []string{"Deployment `api`", "*staging*", "All 4 pods"} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("restartSummary() = %q, expected it to contain %q", summary, expected)
+		}
+	}
+}
+
+func TestRollbackSummary(t *testing.T) {
+	current := &k8s.DeploymentRevision{Number: 7, Images: []string{"api:v2"}}
+	target := &k8s.DeploymentRevision{Number: 6, Images: []string{"api:v1", "sidecar:v1"}}
+
+	summary := rollbackSummary("api", "prod", current, target)
+	for _, expected := range []string{"Current: revision 7 (`api:v2`)", "Target: revision 6 (`api:v1`, `sidecar:v1`)"} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("rollbackSummary() = %q, expected it to contain %q", summary, expected)
+		}
+	}
+
+	// The current revision is omitted when it is unknown
+	summary = rollbackSummary("api", "prod", nil, target)
+	if strings.Contains(summary, "Current:") {
+		t.Errorf("rollbackSummary() = %q, expected no current revision", summary)
+	}
+}
+
+func TestCronJobSummary(t *testing.T) {
+	tests := []struct {
+		command  string
+		expected string
+	}{
+		{"trigger", "Trigger CronJob `backup`"},
+		{"suspend", "Suspend CronJob `backup`"},
+		{"resume", "Resume CronJob `backup`"},
+	}
+
+	for _, tt := range tests {
+		summary := cronJobSummary(tt.command, "backup", "prod")
+		if !strings.Contains(summary, tt.expected) || !strings.Contains(summary, "*prod*") {
+			t.Errorf("cronJobSummary(%q) = %q, expected it to contain %q", tt.command, summary, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"kubectl-bot/internal/approval"
+	"kubectl-bot/internal/rbac"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+const (
+	// defaultElevationDuration is how long an elevation lasts without --for
+	defaultElevationDuration = time.Hour
+
+	// maxElevationDuration caps how long an elevation may last
+	maxElevationDuration = 4 * time.Hour
+)
+
+// handleElevate handles the /elevate command: a temporary, justified elevation of
+// the user's own permissions that is reverted automatically
+func (b *Bot) handleElevate(ctx context.Context, message *tgbotapi.Message) {
+	userID := message.From.ID
+	args, err := splitQuotedArgs(message.CommandArguments())
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ %v", err))
+		return
+	}
+
+	usage := "Usage: /elevate <role|verb resource> [-n <namespace>] [--for <duration>] --reason \"<why>\""
+
+	target := []string{}
+	namespace := "default"
+	elevationDuration := defaultElevationDuration
+	reason := ""
+
+	// Parse the target and flags
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-n" && i+1 < len(args) {
+			namespace = args[i+1]
+			i++
+		} else if args[i] == "--for" && i+1 < len(args) {
+			elevationDuration, err = parseGrantDuration(args[i+1])
+			if err != nil {
+				b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ %v", err))
+				return
+			}
+			i++
+		} else if args[i] == "--reason" && i+1 < len(args) {
+			reason = args[i+1]
+			i++
+		} else {
+			target = append(target, args[i])
+		}
+	}
+
+	if len(target) == 0 {
+		b.sendMessage(message.Chat.ID, usage)
+		return
+	}
+	if strings.TrimSpace(reason) == "" {
+		b.sendMessage(message.Chat.ID, "❌ A reason is required (--reason \"...\")\n\n"+usage)
+		return
+	}
+	if elevationDuration > maxElevationDuration {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ An elevation may last at most %s", duration.HumanDuration(maxElevationDuration)))
+		return
+	}
+
+	namespace = rbac.NormalizeNamespace(namespace)
+
+	resources, _, err := rbac.ElevationScope(strings.Join(target, " "))
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ %v", err))
+		return
+	}
+
+	if b.isAdmin(ctx, userID) {
+		b.sendMessage(message.Chat.ID, "ℹ️ You are already an admin, there is nothing to elevate")
+		return
+	}
+
+	action := approval.Action{
+		Command:   "elevate",
+		Resource:  resources[0],
+		Namespace: namespace,
+		Name:      strings.Join(target, " "),
+		Duration:  elevationDuration.String(),
+		Reason:    reason,
+	}
+
+	allowed, denyReason, err := b.validator.CheckPermission(ctx, action.PermissionCheck(userID))
+	if err != nil || !allowed {
+		b.sendMessage(message.Chat.ID, rbac.FormatPermissionDenied(denyReason))
+		return
+	}
+
+	if b.config.ElevationNeedsApproval(namespace) {
+		b.requestApproval(ctx, message.Chat.ID, message.From, action, elevationSummary(action, message.From.String()))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, b.applyElevation(ctx, message.From.ID, message.From.String(), action, 0))
+}
+
+// applyElevation grants an elevation to a user, tells all admins about it and
+// returns the reply to show. approvedBy is 0 if no approval was needed.
+func (b *Bot) applyElevation(ctx context.Context, userID int64, userName string, action approval.Action, approvedBy int64) string {
+	elevationDuration, err := time.ParseDuration(action.Duration)
+	if err != nil {
+		return fmt.Sprintf("❌ Error: invalid elevation duration: %v", err)
+	}
+
+	elevation, err := rbac.NewElevation(action.Name, action.Namespace, action.Reason, time.Now(), elevationDuration)
+	if err != nil {
+		return fmt.Sprintf("❌ Error: %v", err)
+	}
+	elevation.ApprovedBy = approvedBy
+
+	granted, err := b.rbac.Elevate(ctx, userID, elevation)
+	if err != nil {
+		return fmt.Sprintf("❌ Error: %v", err)
+	}
+
+	log.Printf("User %d elevated to %q in namespace %s until %s: %s",
+		userID, granted.Target, action.Namespace, granted.ExpiresAt.UTC().Format(time.RFC3339), granted.Reason)
+
+	b.notifyAdmins(ctx, fmt.Sprintf("🚨 *Elevation* by `%s` (`%d`)\n\n%s", userName, userID, formatElevation(*granted)))
+
+	return fmt.Sprintf("✅ Elevated to `%s` in namespace *%s* for %s\n\nIt is reverted automatically at %s.",
+		granted.Target, action.Namespace, duration.HumanDuration(elevationDuration), granted.ExpiresAt.UTC().Format(time.RFC3339))
+}
+
+// notifyAdmins sends text to the private chats of all bootstrap admins and admin users
+func (b *Bot) notifyAdmins(ctx context.Context, text string) {
+	// A user's private chat has the same ID as the user
+	for _, id := range b.adminIDs(ctx) {
+		b.sendMessage(id, text)
+	}
+}
+
+// adminIDs returns the IDs of all bootstrap admins and admin users
+func (b *Bot) adminIDs(ctx context.Context) []int64 {
+	admins := map[int64]bool{}
+	for _, id := range b.config.AdminTelegramIDs {
+		admins[id] = true
+	}
+
+	permissions, err := b.rbac.ListUserPermissions(ctx)
+	if err != nil {
+		log.Printf("Failed to list admins to notify: %v", err)
+	}
+	for _, permission := range permissions {
+		if permission.Spec.Role == "admin" {
+			admins[permission.Spec.TelegramUserID] = true
+		}
+	}
+
+	ids := make([]int64, 0, len(admins))
+	for id := range admins {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// isAdmin checks if a user is a bootstrap admin or has the admin role
+func (b *Bot) isAdmin(ctx context.Context, userID int64) bool {
+	if b.rbac.IsBootstrapAdmin(userID) {
+		return true
+	}
+
+	permission, err := b.rbac.GetUserPermission(ctx, userID)
+	return err == nil && permission.Spec.Role == "admin"
+}
+
+// elevationSummary describes a requested elevation. The reason is free text, so it
+// is escaped: a stray _ or * would make Telegram drop the whole message.
+func elevationSummary(action approval.Action, userName string) string {
+	return fmt.Sprintf("🚨 *Elevate `%s`* to `%s` in namespace *%s* for %s?\n\nReason: %s",
+		userName, action.Name, action.Namespace, action.Duration, escapeReason(action.Reason))
+}
+
+// formatElevation describes a granted elevation
+func formatElevation(e rbac.Elevation) string {
+	text := fmt.Sprintf("Target: `%s`\nNamespace: %s\nUntil: %s\nReason: %s",
+		e.Target, e.Permission.Namespace, e.ExpiresAt.UTC().Format(time.RFC3339), escapeReason(e.Reason))
+	if e.ApprovedBy != 0 {
+		text += fmt.Sprintf("\nApproved by: `%d`", e.ApprovedBy)
+	}
+	return text
+}
+
+// formatEndedElevations tells admins which elevations of a user were reverted
+func formatEndedElevations(userID int64, elevations []rbac.Elevation) string {
+	text := fmt.Sprintf("⌛ *Elevation ended* for user `%d`\n", userID)
+	for _, e := range elevations {
+		text += fmt.Sprintf("• `%s` in namespace %s (reason: %s)\n", e.Target, e.Permission.Namespace, escapeReason(e.Reason))
+	}
+	return text + "\nThe temporary permissions have been removed."
+}
+
+// escapeReason escapes the free-text reason of an elevation for Markdown
+func escapeReason(reason string) string {
+	return tgbotapi.EscapeText(tgbotapi.ModeMarkdown, reason)
+}
+
+// splitQuotedArgs splits command arguments at whitespace, keeping text in double
+// quotes together so a reason can contain spaces. Telegram clients often turn
+// typed quotes into curly ones, so those count as well.
+func splitQuotedArgs(s string) ([]string, error) {
+	args := []string{}
+	var current strings.Builder
+	inQuotes := false
+	hasArg := false
+
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '“' || r == '”':
+			inQuotes = !inQuotes
+			hasArg = true
+		case (r == ' ' || r == '\t' || r == '\n') && !inQuotes:
+			if hasArg {
+				args = append(args, current.String())
+				current.Reset()
+				hasArg = false
+			}
+		default:
+			current.WriteRune(r)
+			hasArg = true
+		}
+	}
+
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if hasArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"kubectl-bot/internal/approval"
+	"kubectl-bot/internal/rbac"
+)
+
+func TestSplitQuotedArgs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+		wantErr  bool
+	}{
+		{`admin -n production`, []string{"admin", "-n", "production"}, false},
+		{`admin --reason "INC-42 api down"`, []string{"admin", "--reason", "INC-42 api down"}, false},
+		{`admin --reason “curly quotes”`, []string{"admin", "--reason", "curly quotes"}, false},
+		{`admin --reason ""`, []string{"admin", "--reason", ""}, false},
+		{`admin --reason "unterminated`, nil, true},
+		{``, []string{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			args, err := splitQuotedArgs(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitQuotedArgs(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(args, tt.expected) {
+				t.Errorf("splitQuotedArgs(%q) = %q, expected %q", tt.input, args, tt.expected)
+			}
+		})
+	}
+}
+
+func TestElevationSummary(t *testing.T) {
+	action := approval.Action{Command: "elevate", Resource: "*", Namespace: "production", Name: "operator", Duration: "1h0m0s", Reason: "INC-42"}
+
+	summary := elevationSummary(action, "alice")
+	for _, expected := range []string{"`alice`", "`operator`", "*production*", "Reason: INC-42"} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("elevationSummary() = %q, expected it to contain %q", summary, expected)
+		}
+	}
+}
+
+func TestElevationMessages_EscapeReason(t *testing.T) {
+	reason := "INC_42 api_gateway *down* `now`"
+	escaped := "INC\\_42 api\\_gateway \\*down\\* \\`now\\`"
+
+	action := approval.Action{Command: "elevate", Resource: "*", Namespace: "production", Name: "operator", Duration: "1h0m0s", Reason: reason}
+	elevation := rbac.Elevation{Target: "operator", Reason: reason, Permission: rbac.Permission{Namespace: "production"}}
+
+	for name, text := range map[string]string{
+		"elevationSummary":      elevationSummary(action, "alice"),
+		"formatElevation":       formatElevation(elevation),
+		"formatEndedElevations": formatEndedElevations(42, []rbac.Elevation{elevation}),
+	} {
+		if !strings.Contains(text, escaped) {
+			t.Errorf("%s() = %q, expected the escaped reason %q", name, text, escaped)
+		}
+	}
+}
+
+func TestFormatEndedElevations(t *testing.T) {
+	text := formatEndedElevations(42, []rbac.Elevation{
+		{Target: "admin", Reason: "outage", Permission: rbac.Permission{Namespace: "production"}},
+	})
+
+	if !strings.Contains(text, "`42`") || !strings.Contains(text, "`admin` in namespace production (reason: outage)") {
+		t.Errorf("formatEndedElevations() = %q", text)
+	}
+}
@@ -0,0 +1,191 @@
+package bot
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// defaultFollowDuration is how long /logs -f runs when --for is not given
+	defaultFollowDuration = 2 * time.Minute
+
+	// maxFollowDuration is the longest allowed --for value
+	maxFollowDuration = 15 * time.Minute
+
+	// followFlushInterval batches new lines to stay within Telegram's edit rate limits
+	followFlushInterval = 3 * time.Second
+
+	// followWindowLines is the number of most recent lines shown in the followed message
+	followWindowLines = 40
+
+	// maxFollowSessionsPerUser and maxFollowSessions cap concurrent follow streams
+	maxFollowSessionsPerUser = 2
+	maxFollowSessions        = 10
+)
+
+// followLimiter caps concurrent log follow sessions per user and globally
+type followLimiter struct {
+	mu      sync.Mutex
+	perUser map[int64]int
+	total   int
+}
+
+// newFollowLimiter creates an empty follow limiter
+func newFollowLimiter() *followLimiter {
+	return &followLimiter{
+		perUser: make(map[int64]int),
+	}
+}
+
+// acquire reserves a follow session for a user
+func (l *followLimiter) acquire(userID int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.total >= maxFollowSessions {
+		return fmt.Errorf("too many log follow sessions are running, try again later")
+	}
+	if l.perUser[userID] >= maxFollowSessionsPerUser {
+		return fmt.Errorf("you already have %d log follow sessions running", maxFollowSessionsPerUser)
+	}
+
+	l.perUser[userID]++
+	l.total++
+	return nil
+}
+
+// release frees a follow session reserved by acquire
+func (l *followLimiter) release(userID int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.perUser[userID]--
+	if l.perUser[userID] <= 0 {
+		delete(l.perUser, userID)
+	}
+	l.total--
+}
+
+// followLogs streams new log lines into a single message that is edited in place
+// until the duration expires, the user presses Stop or the bot shuts down
+func (b *Bot) followLogs(ctx context.Context, chatID, userID int64, req logsArgs) {
+	if err := b.follows.acquire(userID); err != nil {
+		b.sendMessage(chatID, fmt.Sprintf("❌ %v", err))
+		return
+	}
+	defer b.follows.release(userID)
+
+	followCtx, cancel := context.WithTimeout(ctx, req.followFor)
+	defer cancel()
+
+	stopped := make(chan struct{})
+	var stopOnce sync.Once
+	stopData := b.callbacks.register(userID, req.followFor, func(ctx context.Context, query *tgbotapi.CallbackQuery) {
+		stopOnce.Do(func() { close(stopped) })
+		cancel()
+	})
+	defer b.callbacks.remove(stopData)
+
+	stopKeyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("⏹ Stop", stopData)),
+	)
+
+	title := fmt.Sprintf("📡 *Following logs for pod* %s (%s)", codeSpan(req.podName), req.describe())
+	messageID := b.sendMessageWithKeyboard(chatID, title+"\n```\nwaiting for logs...\n```", stopKeyboard)
+	if messageID == 0 {
+		return
+	}
+
+	stream, err := b.k8sClient.StreamPodLogs(followCtx, req.namespace, req.podName, req.options)
+	if err != nil {
+		b.editMessage(chatID, messageID, fmt.Sprintf("%s\n\n❌ Error: %v", title, err), nil)
+		return
+	}
+	defer stream.Close()
+
+	// Read lines in the background so flushing is not blocked by a quiet stream
+	// streamErr is only read after lines is closed
+	lines := make(chan string, 256)
+	var streamErr error
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(stream)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-followCtx.Done():
+				return
+			}
+		}
+		streamErr = scanner.Err()
+	}()
+
+	ticker := time.NewTicker(followFlushInterval)
+	defer ticker.Stop()
+
+	window := []string{}
+	dirty := false
+	status := ""
+
+	for status == "" {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				status = "🔚 Log stream ended"
+				if followCtx.Err() != nil {
+					status = followStatus(ctx, stopped, req.followFor)
+				} else if streamErr != nil {
+					status = fmt.Sprintf("⚠️ %v", streamErr)
+				}
+				break
+			}
+			if !req.filter.Matches(line) {
+				continue
+			}
+			window = append(window, line)
+			if len(window) > followWindowLines {
+				window = window[len(window)-followWindowLines:]
+			}
+			dirty = true
+		case <-ticker.C:
+			if dirty {
+				b.editMessage(chatID, messageID, renderFollowWindow(title, window), &stopKeyboard)
+				dirty = false
+			}
+		case <-followCtx.Done():
+			status = followStatus(ctx, stopped, req.followFor)
+		}
+	}
+
+	b.editMessage(chatID, messageID, renderFollowWindow(title, window)+"\n"+status, nil)
+}
+
+// followStatus explains why a follow session ended
+func followStatus(botCtx context.Context, stopped <-chan struct{}, duration time.Duration) string {
+	select {
+	case <-stopped:
+		return "⏹ Stopped"
+	default:
+	}
+
+	if botCtx.Err() != nil {
+		return "⏹ Bot is shutting down"
+	}
+	return fmt.Sprintf("⌛ Finished after %s", duration)
+}
+
+// renderFollowWindow formats the most recent lines of a follow session
+func renderFollowWindow(title string, window []string) string {
+	body := tailPreview(strings.Join(window, "\n"), followWindowLines, maxMessageLength-len(title)-64)
+	if body == "" {
+		body = "waiting for logs..."
+	}
+	return fmt.Sprintf("%s\n```\n%s\n```", title, body)
+}
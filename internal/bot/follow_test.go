@@ -0,0 +1,46 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFollowLimiter(t *testing.T) {
+	limiter := newFollowLimiter()
+
+	for i := 0; i < maxFollowSessionsPerUser; i++ {
+		if err := limiter.acquire(1); err != nil {
+			t.Fatalf("Session %d should be allowed: %v", i+1, err)
+		}
+	}
+	if err := limiter.acquire(1); err == nil {
+		t.Error("Per-user cap should reject another session")
+	}
+
+	limiter.release(1)
+	if err := limiter.acquire(1); err != nil {
+		t.Errorf("Released session should free a slot: %v", err)
+	}
+
+	// Fill the global cap with other users
+	for userID := int64(2); limiter.total < maxFollowSessions; userID++ {
+		if err := limiter.acquire(userID); err != nil {
+			t.Fatalf("User %d should get a session: %v", userID, err)
+		}
+	}
+	if err := limiter.acquire(1000); err == nil {
+		t.Error("Global cap should reject another session")
+	}
+}
+
+func TestRenderFollowWindow(t *testing.T) {
+	if result := renderFollowWindow("title", nil); !strings.Contains(result, "waiting for logs...") {
+		t.Errorf("Empty window should show a placeholder, got %q", result)
+	}
+
+	window := []string{"line one", "line two"}
+	result := renderFollowWindow("title", window)
+	if !strings.HasPrefix(result, "title\n```\n") || !strings.Contains(result, "line one\nline two") {
+		t.Errorf("Unexpected rendering: %q", result)
+	}
+}
@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"kubectl-bot/internal/rbac"
+)
+
+// grantSweepInterval is how often expired time-bound permissions are pruned
+const grantSweepInterval = time.Minute
+
+// runGrantSweeper periodically prunes expired permissions and tells their users
+func (b *Bot) runGrantSweeper(ctx context.Context) {
+	ticker := time.NewTicker(grantSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		b.pruneExpiredGrants(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pruneExpiredGrants runs one pass of the grant sweeper
+func (b *Bot) pruneExpiredGrants(ctx context.Context) {
+	// Teams from older versions or edited with kubectl get their member labels here
+	if indexed, err := b.rbac.IndexTeamMembers(ctx); err != nil {
+		log.Printf("Failed to index team members: %v", err)
+	} else if indexed > 0 {
+		log.Printf("Updated the member labels of %d teams", indexed)
+	}
+
+	pruned, err := b.rbac.PruneExpiredPermissions(ctx, time.Now())
+	if err != nil {
+		log.Printf("Failed to prune expired permissions: %v", err)
+	}
+
+	for _, grants := range pruned {
+		log.Printf("Pruned %d expired permissions and %d elevations of user %d",
+			len(grants.Permissions), len(grants.Elevations), grants.TelegramUserID)
+
+		// A user's private chat has the same ID as the user
+		if len(grants.Permissions) > 0 {
+			b.sendMessage(grants.TelegramUserID, formatExpiredGrants(grants.Permissions))
+		}
+
+		// Admins were told about the elevation, so they are told when it ends
+		if len(grants.Elevations) > 0 {
+			b.notifyAdmins(ctx, formatEndedElevations(grants.TelegramUserID, grants.Elevations))
+		}
+	}
+}
+
+// formatExpiredGrants tells a user which of their temporary permissions expired
+func formatExpiredGrants(permissions []rbac.Permission) string {
+	text := "⌛ Your temporary access has expired:\n"
+	for _, p := range permissions {
+		text += fmt.Sprintf("• %s %s in namespace %s",
+			codeSpan(strings.Join(p.Verbs, ", ")), codeSpan(strings.Join(p.Resources, ", ")), codeSpan(p.Namespace))
+		if len(p.ResourceNames) > 0 {
+			text += fmt.Sprintf(" (names %s)", codeSpan(strings.Join(p.ResourceNames, ", ")))
+		}
+		if p.Selector != "" {
+			text += fmt.Sprintf(" (selector %s)", codeSpan(p.Selector))
+		}
+		text += "\n"
+	}
+	return text + "\nAsk an admin if you still need it."
+}
@@ -0,0 +1,26 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"kubectl-bot/internal/rbac"
+)
+
+func TestFormatExpiredGrants(t *testing.T) {
+	text := formatExpiredGrants([]rbac.Permission{
+		{Namespace: "staging", Resources: []string{"deployments"}, Verbs: []string{"restart", "scale"}},
+		{Namespace: "production", Resources: []string{"pods"}, Verbs: []string{"logs"}, Selector: "app=api"},
+		{Namespace: "*", Resources: []string{"*"}, Verbs: []string{"*"}, ResourceNames: []string{"api-*"}},
+	})
+
+	for _, expected := range []string{
+		"`restart, scale` `deployments` in namespace `staging`",
+		"`logs` `pods` in namespace `production` (selector `app=api`)",
+		"`*` `*` in namespace `*` (names `api-*`)",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("formatExpiredGrants() = %q, expected it to contain %q", text, expected)
+		}
+	}
+}
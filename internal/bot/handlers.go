@@ -2,23 +2,27 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"kubectl-bot/internal/approval"
+	"kubectl-bot/internal/k8s"
 	"kubectl-bot/internal/rbac"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
 )
 
 // handleStart handles the /start command
 func (b *Bot) handleStart(ctx context.Context, message *tgbotapi.Message) {
-	userID := message.From.ID
-	role := b.getUserRole(ctx, userID)
-
-	response := fmt.Sprintf("👋 Welcome to Kubernetes Bot!\n\n"+
-		"Your role: *%s*\n"+
-		"User ID: `%d`\n\n"+
-		"Type /help to see available commands.", role, userID)
+	response := "👋 Welcome to Kubernetes Bot!\n\n" +
+		b.describeUser(ctx, message.From.ID) +
+		"\nType /help to see available commands, /whoami to see this again and /can\\_i to check a permission."
 
 	b.sendMessage(message.Chat.ID, response)
 }
@@ -29,27 +33,57 @@ func (b *Bot) handleHelp(ctx context.Context, message *tgbotapi.Message) {
 
 *Resource Queries:*
 /namespaces - List accessible namespaces
-/pods [namespace] - List pods
-/deployments [namespace] - List deployments
-/services [namespace] - List services
+/pods [namespace] [-l <selector>] [-A] - List pods
+/deployments [namespace] [-l <selector>] [-A] - List deployments
+/statefulsets [namespace] - List statefulsets
+/daemonsets [namespace] - List daemonsets
+/cronjobs [namespace] - List cronjobs
+/jobs [namespace] - List jobs
+/events [namespace] [-o <kind>/<name>] [--warnings] - List events
+/services [namespace] [-l <selector>] [-A] - List services
+/service <name> [-n <namespace>] - Show service details
 
 *Operations:*
-/logs <pod> [-n <namespace>] - Get pod logs
-/restart <deployment> [-n <namespace>] - Restart deployment
+/logs <pod> [-n <namespace>] [-c <container>] [--previous] [--since <duration>] [--tail <lines>] [-f [--for <duration>]] - Get or follow pod logs
+/logs deploy/<name> | -l <selector> [-n <namespace>] - Merge logs of all matching pods
+/logs ... [--grep <regex>] [--invert] [--level error|warn] - Filter log lines
+/restart [sts/|ds/]<name> [-n <namespace>] - Restart deployment, statefulset or daemonset
 /rollback <deployment> [-n <namespace>] - Rollback deployment
-/scale <deployment> <replicas> [-n <namespace>] - Scale deployment
+/scale [sts/]<name> <replicas> [-n <namespace>] - Scale deployment or statefulset
+/trigger <cronjob> [-n <namespace>] - Run a cronjob now
+/suspend <cronjob> [-n <namespace>] - Suspend a cronjob
+/resume <cronjob> [-n <namespace>] - Resume a cronjob
+/elevate <role|verb resource> [-n <namespace>] [--for <duration>] --reason "<why>" - Temporarily elevate your access
+
+*Your Access:*
+/whoami - Show your role, namespaces and granted verbs
+/can\_i <verb> <resource> [name] [-n <namespace>] - Check a permission and what decides it
 
 *Admin Commands:*
-/grant <user_id> <verb> <resource> [-n <namespace>] [-l <selector>] - Grant permission
-/revoke <user_id> <verb> <resource> [-n <namespace>] - Revoke permission
-/permissions [user_id] - Show user permissions
+/grant <user\_id> <verb> <resource> [-n <namespace>] [-l <selector>] [--name <pattern>] [--for <duration>] - Grant permission
+/revoke <user\_id> <verb> <resource> [-n <namespace>] - Revoke permission
+/setrole <user\_id> <viewer|operator|admin> - Set a user's role
+/team create|add|remove|show [name] [user\_id] - Manage teams sharing permissions
+/permissions [user\_id] - Show user permissions
 /selfupdate - Update bot to latest image
 
 *Examples:*
 /pods production
+/pods -A -l app=frontend
 /logs frontend-pod-abc -n production
+/logs frontend-pod-abc -n production -c nginx --since 10m
+/logs api-pod-xyz -n staging -f --for 5m
+/logs deploy/api -n production --since 15m
+/logs deploy/api -n production --level error --grep timeout
 /restart api-deployment -n staging
+/scale sts/postgres 3 -n production
+/restart ds/fluent-bit -n logging
+/trigger nightly-backup -n production
+/events production -o pod/api-7d9f --warnings
 /grant 123456789 logs pods -n production -l app=frontend
+/grant 123456789 restart deployments -n production --for 8h
+/grant 123456789 restart deployments -n production --name api-*
+/elevate operator -n production --for 1h --reason "INC-42 api outage"
 `
 
 	b.sendMessage(message.Chat.ID, help)
@@ -80,6 +114,111 @@ func (b *Bot) handleNamespaces(ctx context.Context, message *tgbotapi.Message) {
 
 // handlePods handles the /pods command
 func (b *Bot) handlePods(ctx context.Context, message *tgbotapi.Message) {
+	userID := message.From.ID
+	opts := parseListArgs(strings.Fields(message.CommandArguments()))
+
+	// Check permission (per namespace of the results when listing all namespaces)
+	if !opts.allNamespaces {
+		allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
+			TelegramUserID: userID,
+			Namespace:      opts.namespace,
+			Resource:       "pods",
+			Verb:           "list",
+			Selector:       opts.selector,
+		})
+
+		if err != nil || !allowed {
+			b.sendMessage(message.Chat.ID, rbac.FormatPermissionDenied(reason))
+			return
+		}
+	}
+
+	// List pods
+	pods, err := b.k8sClient.ListPods(ctx, opts.listNamespace(), opts.selector)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
+		return
+	}
+
+	// Only show objects matching the user's permitted names and selectors
+	canList := b.validator.ListFilter(ctx, userID, "pods", "list")
+
+	now := time.Now()
+	response := ""
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !canList(pod.Namespace, pod.Name, pod.Labels) {
+			continue
+		}
+
+		summary := k8s.SummarizePod(pod, now)
+		summary.Name = opts.displayName(pod.Namespace, pod.Name)
+		response += formatPodSummary(summary)
+	}
+
+	if response == "" {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("No pods found in %s", opts.scope()))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("*Pods* in %s:\n\n", opts.scope())+response)
+}
+
+// handleDeployments handles the /deployments command
+func (b *Bot) handleDeployments(ctx context.Context, message *tgbotapi.Message) {
+	userID := message.From.ID
+	opts := parseListArgs(strings.Fields(message.CommandArguments()))
+
+	// Check permission (per namespace of the results when listing all namespaces)
+	if !opts.allNamespaces {
+		allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
+			TelegramUserID: userID,
+			Namespace:      opts.namespace,
+			Resource:       "deployments",
+			Verb:           "list",
+			Selector:       opts.selector,
+		})
+
+		if err != nil || !allowed {
+			b.sendMessage(message.Chat.ID, rbac.FormatPermissionDenied(reason))
+			return
+		}
+	}
+
+	// List deployments
+	deployments, err := b.k8sClient.ListDeployments(ctx, opts.listNamespace(), opts.selector)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
+		return
+	}
+
+	// Only show objects matching the user's permitted names and selectors
+	canList := b.validator.ListFilter(ctx, userID, "deployments", "list")
+
+	response := ""
+	for _, dep := range deployments.Items {
+		if !canList(dep.Namespace, dep.Name, dep.Labels) {
+			continue
+		}
+
+		replicas := int32(0)
+		if dep.Spec.Replicas != nil {
+			replicas = *dep.Spec.Replicas
+		}
+		response += fmt.Sprintf("🚀 `%s`\n   Replicas: %d/%d\n\n",
+			opts.displayName(dep.Namespace, dep.Name), dep.Status.ReadyReplicas, replicas)
+	}
+
+	if response == "" {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("No deployments found in %s", opts.scope()))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("*Deployments* in %s:\n\n", opts.scope())+response)
+}
+
+// handleStatefulSets handles the /statefulsets command
+func (b *Bot) handleStatefulSets(ctx context.Context, message *tgbotapi.Message) {
 	userID := message.From.ID
 	args := strings.Fields(message.CommandArguments())
 
@@ -94,7 +233,7 @@ func (b *Bot) handlePods(ctx context.Context, message *tgbotapi.Message) {
 	allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
 		TelegramUserID: userID,
 		Namespace:      namespace,
-		Resource:       "pods",
+		Resource:       "statefulsets",
 		Verb:           "list",
 	})
 
@@ -103,29 +242,40 @@ func (b *Bot) handlePods(ctx context.Context, message *tgbotapi.Message) {
 		return
 	}
 
-	// List pods
-	pods, err := b.k8sClient.ListPods(ctx, namespace, "")
+	// List statefulsets
+	statefulSets, err := b.k8sClient.ListStatefulSets(ctx, namespace, "")
 	if err != nil {
 		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
 		return
 	}
 
-	if len(pods.Items) == 0 {
-		b.sendMessage(message.Chat.ID, fmt.Sprintf("No pods found in namespace *%s*", namespace))
-		return
+	// Only show statefulsets matching the user's permitted selectors
+	canList := b.validator.ListFilter(ctx, userID, "statefulsets", "list")
+
+	response := ""
+	for _, sts := range statefulSets.Items {
+		if !canList(sts.Namespace, sts.Name, sts.Labels) {
+			continue
+		}
+
+		replicas := int32(0)
+		if sts.Spec.Replicas != nil {
+			replicas = *sts.Spec.Replicas
+		}
+		response += fmt.Sprintf("🗄 `%s`\n   Replicas: %d/%d\n\n",
+			sts.Name, sts.Status.ReadyReplicas, replicas)
 	}
 
-	response := fmt.Sprintf("*Pods in namespace %s:*\n\n", namespace)
-	for _, pod := range pods.Items {
-		status := string(pod.Status.Phase)
-		response += fmt.Sprintf("📦 `%s`\n   Status: %s\n\n", pod.Name, status)
+	if response == "" {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("No statefulsets found in namespace *%s*", namespace))
+		return
 	}
 
-	b.sendMessage(message.Chat.ID, response)
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("*StatefulSets in namespace %s:*\n\n", namespace)+response)
 }
 
-// handleDeployments handles the /deployments command
-func (b *Bot) handleDeployments(ctx context.Context, message *tgbotapi.Message) {
+// handleDaemonSets handles the /daemonsets command
+func (b *Bot) handleDaemonSets(ctx context.Context, message *tgbotapi.Message) {
 	userID := message.From.ID
 	args := strings.Fields(message.CommandArguments())
 
@@ -140,7 +290,7 @@ func (b *Bot) handleDeployments(ctx context.Context, message *tgbotapi.Message)
 	allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
 		TelegramUserID: userID,
 		Namespace:      namespace,
-		Resource:       "deployments",
+		Resource:       "daemonsets",
 		Verb:           "list",
 	})
 
@@ -149,33 +299,158 @@ func (b *Bot) handleDeployments(ctx context.Context, message *tgbotapi.Message)
 		return
 	}
 
-	// List deployments
-	deployments, err := b.k8sClient.ListDeployments(ctx, namespace, "")
+	// List daemonsets
+	daemonSets, err := b.k8sClient.ListDaemonSets(ctx, namespace, "")
 	if err != nil {
 		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
 		return
 	}
 
-	if len(deployments.Items) == 0 {
-		b.sendMessage(message.Chat.ID, fmt.Sprintf("No deployments found in namespace *%s*", namespace))
+	// Only show daemonsets matching the user's permitted selectors
+	canList := b.validator.ListFilter(ctx, userID, "daemonsets", "list")
+
+	response := ""
+	for _, ds := range daemonSets.Items {
+		if !canList(ds.Namespace, ds.Name, ds.Labels) {
+			continue
+		}
+
+		response += fmt.Sprintf("🛰 `%s`\n   Desired: %d  Current: %d  Ready: %d  Updated: %d\n\n",
+			ds.Name, ds.Status.DesiredNumberScheduled, ds.Status.CurrentNumberScheduled,
+			ds.Status.NumberReady, ds.Status.UpdatedNumberScheduled)
+	}
+
+	if response == "" {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("No daemonsets found in namespace *%s*", namespace))
 		return
 	}
 
-	response := fmt.Sprintf("*Deployments in namespace %s:*\n\n", namespace)
-	for _, dep := range deployments.Items {
-		replicas := int32(0)
-		if dep.Spec.Replicas != nil {
-			replicas = *dep.Spec.Replicas
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("*DaemonSets in namespace %s:*\n\n", namespace)+response)
+}
+
+// handleServices handles the /services command
+func (b *Bot) handleServices(ctx context.Context, message *tgbotapi.Message) {
+	userID := message.From.ID
+	opts := parseListArgs(strings.Fields(message.CommandArguments()))
+
+	// Check permission (per namespace of the results when listing all namespaces)
+	if !opts.allNamespaces {
+		allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
+			TelegramUserID: userID,
+			Namespace:      opts.namespace,
+			Resource:       "services",
+			Verb:           "list",
+			Selector:       opts.selector,
+		})
+
+		if err != nil || !allowed {
+			b.sendMessage(message.Chat.ID, rbac.FormatPermissionDenied(reason))
+			return
 		}
-		response += fmt.Sprintf("🚀 `%s`\n   Replicas: %d/%d\n\n",
-			dep.Name, dep.Status.ReadyReplicas, replicas)
+	}
+
+	// List services
+	services, err := b.k8sClient.ListServices(ctx, opts.listNamespace(), opts.selector)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
+		return
+	}
+
+	// Only show objects matching the user's permitted names and selectors
+	canList := b.validator.ListFilter(ctx, userID, "services", "list")
+
+	response := ""
+	for _, svc := range services.Items {
+		if !canList(svc.Namespace, svc.Name, svc.Labels) {
+			continue
+		}
+
+		response += fmt.Sprintf("🌐 `%s`\n   Type: %s\n\n", opts.displayName(svc.Namespace, svc.Name), svc.Spec.Type)
+	}
+
+	if response == "" {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("No services found in %s", opts.scope()))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("*Services* in %s:\n\n", opts.scope())+response)
+}
+
+// handleService handles the /service command
+func (b *Bot) handleService(ctx context.Context, message *tgbotapi.Message) {
+	userID := message.From.ID
+	args := strings.Fields(message.CommandArguments())
+
+	if len(args) == 0 {
+		b.sendMessage(message.Chat.ID, "Usage: /service <name> [-n <namespace>]")
+		return
+	}
+
+	serviceName := args[0]
+	namespace := "default"
+
+	// Parse flags
+	for i := 1; i < len(args); i++ {
+		if args[i] == "-n" && i+1 < len(args) {
+			namespace = args[i+1]
+			i++
+		}
+	}
+
+	namespace = rbac.NormalizeNamespace(namespace)
+
+	// Check permission
+	allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
+		TelegramUserID: userID,
+		Namespace:      namespace,
+		Resource:       "services",
+		Verb:           "get",
+		ResourceName:   serviceName,
+	})
+
+	if err != nil || !allowed {
+		b.sendMessage(message.Chat.ID, rbac.FormatPermissionDenied(reason))
+		return
+	}
+
+	svc, err := b.k8sClient.GetService(ctx, namespace, serviceName)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
+		return
+	}
+
+	response := fmt.Sprintf("🌐 *Service %s (namespace: %s)*\n\n", svc.Name, svc.Namespace)
+	response += fmt.Sprintf("Type: %s\n", svc.Spec.Type)
+
+	clusterIP := svc.Spec.ClusterIP
+	if clusterIP == "" {
+		clusterIP = "<none>"
+	}
+	response += fmt.Sprintf("Cluster IP: `%s`\n", clusterIP)
+
+	if external := k8s.ServiceExternalAddresses(svc); len(external) > 0 {
+		response += fmt.Sprintf("External: `%s`\n", strings.Join(external, ", "))
+	}
+
+	if len(svc.Spec.Ports) > 0 {
+		response += "Ports:\n"
+		for _, port := range svc.Spec.Ports {
+			response += "   " + formatServicePort(port.Name, port.Port, port.TargetPort.String(), string(port.Protocol), port.NodePort) + "\n"
+		}
+	}
+
+	ready, total, err := b.k8sClient.CountServiceEndpoints(ctx, namespace, serviceName)
+	if err != nil {
+		response += fmt.Sprintf("Endpoints: unavailable (%v)\n", err)
+	} else {
+		response += fmt.Sprintf("Endpoints: %d/%d ready\n", ready, total)
 	}
 
 	b.sendMessage(message.Chat.ID, response)
 }
 
-// handleServices handles the /services command
-func (b *Bot) handleServices(ctx context.Context, message *tgbotapi.Message) {
+// handleCronJobs handles the /cronjobs command
+func (b *Bot) handleCronJobs(ctx context.Context, message *tgbotapi.Message) {
 	userID := message.From.ID
 	args := strings.Fields(message.CommandArguments())
 
@@ -190,7 +465,7 @@ func (b *Bot) handleServices(ctx context.Context, message *tgbotapi.Message) {
 	allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
 		TelegramUserID: userID,
 		Namespace:      namespace,
-		Resource:       "services",
+		Resource:       "cronjobs",
 		Verb:           "list",
 	})
 
@@ -199,37 +474,150 @@ func (b *Bot) handleServices(ctx context.Context, message *tgbotapi.Message) {
 		return
 	}
 
-	// List services
-	services, err := b.k8sClient.ListServices(ctx, namespace, "")
+	// List cronjobs
+	cronJobs, err := b.k8sClient.ListCronJobs(ctx, namespace, "")
 	if err != nil {
 		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
 		return
 	}
 
-	if len(services.Items) == 0 {
-		b.sendMessage(message.Chat.ID, fmt.Sprintf("No services found in namespace *%s*", namespace))
+	// Only show cronjobs matching the user's permitted selectors
+	canList := b.validator.ListFilter(ctx, userID, "cronjobs", "list")
+
+	response := ""
+	for _, cj := range cronJobs.Items {
+		if !canList(cj.Namespace, cj.Name, cj.Labels) {
+			continue
+		}
+
+		suspended := cj.Spec.Suspend != nil && *cj.Spec.Suspend
+		lastSchedule := "never"
+		if cj.Status.LastScheduleTime != nil {
+			lastSchedule = cj.Status.LastScheduleTime.UTC().Format("2006-01-02 15:04 MST")
+		}
+		response += fmt.Sprintf("⏰ `%s`\n   Schedule: `%s`\n   Suspended: %v  Active: %d\n   Last run: %s\n\n",
+			cj.Name, cj.Spec.Schedule, suspended, len(cj.Status.Active), lastSchedule)
+	}
+
+	if response == "" {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("No cronjobs found in namespace *%s*", namespace))
 		return
 	}
 
-	response := fmt.Sprintf("*Services in namespace %s:*\n\n", namespace)
-	for _, svc := range services.Items {
-		response += fmt.Sprintf("🌐 `%s`\n   Type: %s\n\n", svc.Name, svc.Spec.Type)
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("*CronJobs in namespace %s:*\n\n", namespace)+response)
+}
+
+// handleJobs handles the /jobs command
+func (b *Bot) handleJobs(ctx context.Context, message *tgbotapi.Message) {
+	userID := message.From.ID
+	args := strings.Fields(message.CommandArguments())
+
+	namespace := "default"
+	if len(args) > 0 {
+		namespace = args[0]
 	}
 
-	b.sendMessage(message.Chat.ID, response)
+	namespace = rbac.NormalizeNamespace(namespace)
+
+	// Check permission
+	allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
+		TelegramUserID: userID,
+		Namespace:      namespace,
+		Resource:       "jobs",
+		Verb:           "list",
+	})
+
+	if err != nil || !allowed {
+		b.sendMessage(message.Chat.ID, rbac.FormatPermissionDenied(reason))
+		return
+	}
+
+	// List jobs
+	jobs, err := b.k8sClient.ListJobs(ctx, namespace, "")
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
+		return
+	}
+
+	// Only show jobs matching the user's permitted selectors
+	canList := b.validator.ListFilter(ctx, userID, "jobs", "list")
+
+	response := ""
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if !canList(job.Namespace, job.Name, job.Labels) {
+			continue
+		}
+
+		completions := int32(1)
+		if job.Spec.Completions != nil {
+			completions = *job.Spec.Completions
+		}
+		response += fmt.Sprintf("⚙️ `%s`\n   Status: %s\n   Completions: %d/%d\n\n",
+			job.Name, k8s.JobStatus(job), job.Status.Succeeded, completions)
+	}
+
+	if response == "" {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("No jobs found in namespace *%s*", namespace))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("*Jobs in namespace %s:*\n\n", namespace)+response)
 }
 
-// handleLogs handles the /logs command
-func (b *Bot) handleLogs(ctx context.Context, message *tgbotapi.Message) {
+// handleTrigger handles the /trigger command
+func (b *Bot) handleTrigger(ctx context.Context, message *tgbotapi.Message) {
+	userID := message.From.ID
+	args := strings.Fields(message.CommandArguments())
+
+	if len(args) == 0 {
+		b.sendMessage(message.Chat.ID, "Usage: /trigger <cronjob> [-n <namespace>]")
+		return
+	}
+
+	cronJobName := args[0]
+	namespace := "default"
+
+	// Parse flags
+	for i := 1; i < len(args); i++ {
+		if args[i] == "-n" && i+1 < len(args) {
+			namespace = args[i+1]
+			i++
+		}
+	}
+
+	namespace = rbac.NormalizeNamespace(namespace)
+
+	// Check permission
+	allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
+		TelegramUserID: userID,
+		Namespace:      namespace,
+		Resource:       "cronjobs",
+		Verb:           "trigger",
+		ResourceName:   cronJobName,
+	})
+
+	if err != nil || !allowed {
+		b.sendMessage(message.Chat.ID, rbac.FormatPermissionDenied(reason))
+		return
+	}
+
+	// Create job from cronjob
+	action := approval.Action{Command: "trigger", Resource: "cronjobs", Namespace: namespace, Name: cronJobName}
+	b.performAction(ctx, message.Chat.ID, message.From, action, cronJobSummary(action.Command, cronJobName, namespace), true)
+}
+
+// handleSuspend handles the /suspend and /resume commands
+func (b *Bot) handleSuspend(ctx context.Context, message *tgbotapi.Message, suspend bool) {
 	userID := message.From.ID
 	args := strings.Fields(message.CommandArguments())
 
 	if len(args) == 0 {
-		b.sendMessage(message.Chat.ID, "Usage: /logs <pod> [-n <namespace>]")
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Usage: /%s <cronjob> [-n <namespace>]", message.Command()))
 		return
 	}
 
-	podName := args[0]
+	cronJobName := args[0]
 	namespace := "default"
 
 	// Parse flags
@@ -242,35 +630,300 @@ func (b *Bot) handleLogs(ctx context.Context, message *tgbotapi.Message) {
 
 	namespace = rbac.NormalizeNamespace(namespace)
 
+	// Check permission (resuming uses the same verb as suspending)
+	allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
+		TelegramUserID: userID,
+		Namespace:      namespace,
+		Resource:       "cronjobs",
+		Verb:           "suspend",
+		ResourceName:   cronJobName,
+	})
+
+	if err != nil || !allowed {
+		b.sendMessage(message.Chat.ID, rbac.FormatPermissionDenied(reason))
+		return
+	}
+
+	command := "resume"
+	if suspend {
+		command = "suspend"
+	}
+
+	action := approval.Action{Command: command, Resource: "cronjobs", Namespace: namespace, Name: cronJobName}
+	b.performAction(ctx, message.Chat.ID, message.From, action, cronJobSummary(command, cronJobName, namespace), true)
+}
+
+// handleEvents handles the /events command
+func (b *Bot) handleEvents(ctx context.Context, message *tgbotapi.Message) {
+	userID := message.From.ID
+	args := strings.Fields(message.CommandArguments())
+
+	namespace := "default"
+	filter := k8s.EventFilter{}
+
+	// Parse arguments
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-o" && i+1 < len(args):
+			kind, name, err := parseObjectRef(args[i+1])
+			if err != nil {
+				b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ %v", err))
+				return
+			}
+			filter.Kind = kind
+			filter.Name = name
+			i++
+		case args[i] == "--warnings":
+			filter.Type = "Warning"
+		case !strings.HasPrefix(args[i], "-"):
+			namespace = args[i]
+		}
+	}
+
+	namespace = rbac.NormalizeNamespace(namespace)
+
 	// Check permission
 	allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
 		TelegramUserID: userID,
 		Namespace:      namespace,
+		Resource:       "events",
+		Verb:           "list",
+	})
+
+	if err != nil || !allowed {
+		b.sendMessage(message.Chat.ID, rbac.FormatPermissionDenied(reason))
+		return
+	}
+
+	// List events
+	events, err := b.k8sClient.ListEvents(ctx, namespace, filter)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
+		return
+	}
+
+	if len(events.Items) == 0 {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("No events found in namespace *%s*", namespace))
+		return
+	}
+
+	// Show only the most recent events
+	items := events.Items
+	if len(items) > maxEvents {
+		items = items[len(items)-maxEvents:]
+	}
+
+	now := time.Now()
+	response := fmt.Sprintf("*Events in namespace %s:*\n\n", namespace)
+	for i := range items {
+		response += formatEvent(&items[i], now)
+	}
+
+	b.sendMessage(message.Chat.ID, response)
+}
+
+// formatEvent renders one event. Reasons and messages often contain image names
+// and paths, so they are escaped for Markdown.
+func formatEvent(event *corev1.Event, now time.Time) string {
+	icon := "ℹ️"
+	if event.Type == "Warning" {
+		icon = "⚠️"
+	}
+
+	count := ""
+	if event.Count > 1 {
+		count = fmt.Sprintf(" (x%d)", event.Count)
+	}
+
+	age := duration.HumanDuration(now.Sub(k8s.EventTime(event)))
+	return fmt.Sprintf("%s %s ago `%s/%s` %s%s\n   %s\n\n",
+		icon, age, event.InvolvedObject.Kind, event.InvolvedObject.Name,
+		tgbotapi.EscapeText(tgbotapi.ModeMarkdown, event.Reason), count,
+		tgbotapi.EscapeText(tgbotapi.ModeMarkdown, strings.TrimSpace(event.Message)))
+}
+
+// handleLogs handles the /logs command
+func (b *Bot) handleLogs(ctx context.Context, message *tgbotapi.Message) {
+	args := strings.Fields(message.CommandArguments())
+
+	if len(args) == 0 {
+		b.sendMessage(message.Chat.ID, "Usage: /logs <pod|deploy/<name>> [-l <selector>] [-n <namespace>] [-c <container>] [--previous] [--since <duration|time>] [--tail <lines>] [-f [--for <duration>]] [--grep <regex>] [--invert] [--level error|warn]")
+		return
+	}
+
+	req, err := parseLogsArgs(args)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ %v", err))
+		return
+	}
+
+	b.showLogs(ctx, message.Chat.ID, message.From.ID, req)
+}
+
+// showLogs checks permission and sends the requested pod logs, asking for a
+// container first when the pod has more than one and none was given
+func (b *Bot) showLogs(ctx context.Context, chatID, userID int64, req logsArgs) {
+	if req.aggregate() {
+		b.showAggregatedLogs(ctx, chatID, userID, req)
+		return
+	}
+
+	// Check permission
+	allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
+		TelegramUserID: userID,
+		Namespace:      req.namespace,
 		Resource:       "pods",
 		Verb:           "logs",
-		ResourceName:   podName,
+		ResourceName:   req.podName,
 	})
 
 	if err != nil || !allowed {
-		b.sendMessage(message.Chat.ID, rbac.FormatPermissionDenied(reason))
+		b.sendMessage(chatID, rbac.FormatPermissionDenied(reason))
+		return
+	}
+
+	if req.options.Container == "" {
+		pod, err := b.k8sClient.GetPod(ctx, req.namespace, req.podName)
+		if err != nil {
+			b.sendMessage(chatID, fmt.Sprintf("❌ Error: %v", err))
+			return
+		}
+
+		if len(pod.Spec.Containers) > 1 {
+			b.sendContainerChoice(chatID, userID, req, pod.Spec.Containers)
+			return
+		}
+	}
+
+	if req.options.Follow {
+		b.followLogs(ctx, chatID, userID, req)
+		return
+	}
+
+	// A stream that fails part way still shows the logs read before the failure
+	logs, err := b.k8sClient.GetPodLogs(ctx, req.namespace, req.podName, req.options)
+	var readErr *k8s.LogReadError
+	if err != nil && !errors.As(err, &readErr) {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Error: %v", err))
+		return
+	}
+
+	title := fmt.Sprintf("*Logs for pod* %s (%s):", codeSpan(req.podName), req.describe())
+	if readErr != nil {
+		title += fmt.Sprintf("\n⚠️ %s", codeSpan(readErr.Error()))
+	}
+
+	logs = req.filter.Apply(logs)
+	if logs == "" && !req.filter.IsEmpty() {
+		b.sendMessage(chatID, title+"\n\nNo matching log lines")
+		return
+	}
+
+	b.sendCodeBlock(chatID, title, logs, req.filename())
+}
+
+// showAggregatedLogs merges the logs of every pod of a workload or label selector
+// that the user may read and sends them as a file
+func (b *Bot) showAggregatedLogs(ctx context.Context, chatID, userID int64, req logsArgs) {
+	// Check namespace access before looking anything up, so the replies cannot
+	// reveal workloads or pods in namespaces the user may not read
+	allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
+		TelegramUserID: userID,
+		Namespace:      req.namespace,
+		Resource:       "pods",
+		Verb:           "logs",
+	})
+	if err != nil || !allowed {
+		b.sendMessage(chatID, rbac.FormatPermissionDenied(reason))
+		return
+	}
+
+	selector := req.selector
+	if req.workload != "" {
+		selector, err = b.k8sClient.WorkloadSelector(ctx, req.namespace, req.workload, req.workloadName)
+		if err != nil {
+			b.sendMessage(chatID, fmt.Sprintf("❌ Error: %v", err))
+			return
+		}
+	}
+
+	pods, err := b.k8sClient.ListPods(ctx, req.namespace, selector)
+	if err != nil {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Error: %v", err))
+		return
+	}
+
+	// Each pod must match the user's logs grants, including name- and
+	// selector-restricted ones and deny rules
+	canRead := b.validator.ListFilter(ctx, userID, "pods", "logs")
+	readable := []corev1.Pod{}
+	denied := 0
+	for _, pod := range pods.Items {
+		if !canRead(pod.Namespace, pod.Name, pod.Labels) {
+			denied++
+			continue
+		}
+		readable = append(readable, pod)
+	}
+
+	if len(readable) == 0 {
+		if denied > 0 {
+			b.sendMessage(chatID, rbac.FormatPermissionDenied("Permission denied: missing 'logs' access to the matching pods"))
+			return
+		}
+		b.sendMessage(chatID, fmt.Sprintf("No pods found (%s)", req.describe()))
+		return
+	}
+
+	if len(readable) > maxAggregatedPods {
+		b.sendMessage(chatID, fmt.Sprintf("❌ %d pods match, at most %d can be aggregated. Narrow the selector.", len(readable), maxAggregatedPods))
 		return
 	}
 
-	// Get logs (last 100 lines)
-	logs, err := b.k8sClient.GetPodLogs(ctx, namespace, podName, 100)
-	if err != nil {
-		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
+	results := b.k8sClient.GetMultiPodLogs(ctx, readable, req.options)
+
+	caption := fmt.Sprintf("*Logs for %d pods* (%s)", len(readable), req.describe())
+	failed := 0
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		failed++
+		if failed <= maxReportedLogErrors {
+			caption += fmt.Sprintf("\n⚠️ %s: %s", result.Pod, codeSpan(result.Err.Error()))
+		}
+	}
+	if failed > maxReportedLogErrors {
+		caption += fmt.Sprintf("\n⚠️ ...and %d more pods failed", failed-maxReportedLogErrors)
+	}
+	if denied > 0 {
+		caption += fmt.Sprintf("\n🔒 %d pods skipped (no permission)", denied)
+	}
+
+	merged := req.filter.Apply(k8s.MergePodLogs(results))
+	if merged == "" {
+		b.sendMessage(chatID, caption+"\n\nNo matching log lines")
 		return
 	}
 
-	response := fmt.Sprintf("*Logs for pod %s (namespace: %s):*\n```\n%s\n```", podName, namespace, logs)
+	b.sendDocument(chatID, req.filename(), merged, caption)
+}
 
-	// Telegram message limit is 4096 chars
-	if len(response) > 4000 {
-		response = response[:4000] + "\n...(truncated)\n```"
+// sendContainerChoice asks the user which container's logs to show
+func (b *Bot) sendContainerChoice(chatID, userID int64, req logsArgs, containers []corev1.Container) {
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for _, container := range containers {
+		choice := req
+		choice.options.Container = container.Name
+
+		data := b.callbacks.register(userID, containerChoiceTTL, func(ctx context.Context, query *tgbotapi.CallbackQuery) {
+			b.showLogs(ctx, query.Message.Chat.ID, query.From.ID, choice)
+		})
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(container.Name, data)))
 	}
 
-	b.sendMessage(message.Chat.ID, response)
+	text := fmt.Sprintf("Pod `%s` has %d containers. Choose one:", req.podName, len(containers))
+	b.sendMessageWithKeyboard(chatID, text, tgbotapi.NewInlineKeyboardMarkup(rows...))
 }
 
 // handleRestart handles the /restart command
@@ -279,11 +932,16 @@ func (b *Bot) handleRestart(ctx context.Context, message *tgbotapi.Message) {
 	args := strings.Fields(message.CommandArguments())
 
 	if len(args) == 0 {
-		b.sendMessage(message.Chat.ID, "Usage: /restart <deployment> [-n <namespace>]")
+		b.sendMessage(message.Chat.ID, "Usage: /restart [sts/|ds/]<name> [-n <namespace>]")
+		return
+	}
+
+	resource, name, err := parseWorkloadRef(args[0])
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ %v", err))
 		return
 	}
 
-	deploymentName := args[0]
 	namespace := "default"
 
 	// Parse flags
@@ -297,27 +955,28 @@ func (b *Bot) handleRestart(ctx context.Context, message *tgbotapi.Message) {
 	namespace = rbac.NormalizeNamespace(namespace)
 
 	// Check permission
-	allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
+	check := rbac.PermissionCheck{
 		TelegramUserID: userID,
 		Namespace:      namespace,
-		Resource:       "deployments",
+		Resource:       resource,
 		Verb:           "restart",
-		ResourceName:   deploymentName,
-	})
+		ResourceName:   name,
+	}
+	allowed, reason, err := b.validator.CheckPermission(ctx, check)
 
 	if err != nil || !allowed {
 		b.sendMessage(message.Chat.ID, rbac.FormatPermissionDenied(reason))
 		return
 	}
 
-	// Restart deployment
-	err = b.k8sClient.RestartDeployment(ctx, namespace, deploymentName)
+	replicas, err := b.k8sClient.WorkloadReplicas(ctx, namespace, resource, name)
 	if err != nil {
 		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
 		return
 	}
 
-	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Deployment `%s` restarted in namespace *%s*", deploymentName, namespace))
+	action := approval.Action{Command: "restart", Resource: resource, Namespace: namespace, Name: name}
+	b.performAction(ctx, message.Chat.ID, message.From, action, restartSummary(resource, name, namespace, replicas), true)
 }
 
 // handleRollback handles the /rollback command
@@ -344,27 +1003,28 @@ func (b *Bot) handleRollback(ctx context.Context, message *tgbotapi.Message) {
 	namespace = rbac.NormalizeNamespace(namespace)
 
 	// Check permission
-	allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
+	check := rbac.PermissionCheck{
 		TelegramUserID: userID,
 		Namespace:      namespace,
 		Resource:       "deployments",
 		Verb:           "rollback",
 		ResourceName:   deploymentName,
-	})
+	}
+	allowed, reason, err := b.validator.CheckPermission(ctx, check)
 
 	if err != nil || !allowed {
 		b.sendMessage(message.Chat.ID, rbac.FormatPermissionDenied(reason))
 		return
 	}
 
-	// Rollback deployment
-	err = b.k8sClient.RollbackDeployment(ctx, namespace, deploymentName)
+	current, target, err := b.k8sClient.GetRollbackTarget(ctx, namespace, deploymentName)
 	if err != nil {
 		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
 		return
 	}
 
-	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Deployment `%s` rolled back in namespace *%s*", deploymentName, namespace))
+	action := approval.Action{Command: "rollback", Resource: "deployments", Namespace: namespace, Name: deploymentName, Revision: target.Number}
+	b.performAction(ctx, message.Chat.ID, message.From, action, rollbackSummary(deploymentName, namespace, current, target), true)
 }
 
 // handleScale handles the /scale command
@@ -373,11 +1033,21 @@ func (b *Bot) handleScale(ctx context.Context, message *tgbotapi.Message) {
 	args := strings.Fields(message.CommandArguments())
 
 	if len(args) < 2 {
-		b.sendMessage(message.Chat.ID, "Usage: /scale <deployment> <replicas> [-n <namespace>]")
+		b.sendMessage(message.Chat.ID, "Usage: /scale [sts/]<name> <replicas> [-n <namespace>]")
+		return
+	}
+
+	resource, name, err := parseWorkloadRef(args[0])
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ %v", err))
+		return
+	}
+
+	if resource == "daemonsets" {
+		b.sendMessage(message.Chat.ID, "❌ DaemonSets cannot be scaled")
 		return
 	}
 
-	deploymentName := args[0]
 	replicas, err := strconv.ParseInt(args[1], 10, 32)
 	if err != nil {
 		b.sendMessage(message.Chat.ID, "❌ Invalid replica count")
@@ -397,28 +1067,34 @@ func (b *Bot) handleScale(ctx context.Context, message *tgbotapi.Message) {
 	namespace = rbac.NormalizeNamespace(namespace)
 
 	// Check permission
-	allowed, reason, err := b.validator.CheckPermission(ctx, rbac.PermissionCheck{
+	check := rbac.PermissionCheck{
 		TelegramUserID: userID,
 		Namespace:      namespace,
-		Resource:       "deployments",
+		Resource:       resource,
 		Verb:           "scale",
-		ResourceName:   deploymentName,
-	})
+		ResourceName:   name,
+	}
+	allowed, reason, err := b.validator.CheckPermission(ctx, check)
 
 	if err != nil || !allowed {
 		b.sendMessage(message.Chat.ID, rbac.FormatPermissionDenied(reason))
 		return
 	}
 
-	// Scale deployment
-	err = b.k8sClient.ScaleDeployment(ctx, namespace, deploymentName, int32(replicas))
+	current, err := b.k8sClient.WorkloadReplicas(ctx, namespace, resource, name)
 	if err != nil {
 		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
 		return
 	}
 
-	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Deployment `%s` scaled to %d replicas in namespace *%s*",
-		deploymentName, replicas, namespace))
+	if current == int32(replicas) {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("ℹ️ %s `%s` already has %d replicas in namespace *%s*",
+			workloadKinds[resource], name, replicas, namespace))
+		return
+	}
+
+	action := approval.Action{Command: "scale", Resource: resource, Namespace: namespace, Name: name, Replicas: int32(replicas)}
+	b.performAction(ctx, message.Chat.ID, message.From, action, scaleSummary(resource, name, namespace, current, int32(replicas)), true)
 }
 
 // handleGrant handles the /grant command (admin only)
@@ -436,7 +1112,7 @@ func (b *Bot) handleGrant(ctx context.Context, message *tgbotapi.Message) {
 	}
 
 	if len(args) < 3 {
-		b.sendMessage(message.Chat.ID, "Usage: /grant <user_id> <verb> <resource> [-n <namespace>] [-l <selector>]")
+		b.sendMessage(message.Chat.ID, "Usage: /grant <user_id> <verb> <resource> [-n <namespace>] [-l <selector>] [--name <pattern>] [--for <duration>]")
 		return
 	}
 
@@ -450,6 +1126,8 @@ func (b *Bot) handleGrant(ctx context.Context, message *tgbotapi.Message) {
 	resource := args[2]
 	namespace := "*"
 	selector := ""
+	names := []string{}
+	var expiresAt *time.Time
 
 	// Parse flags
 	for i := 3; i < len(args); i++ {
@@ -459,11 +1137,23 @@ func (b *Bot) handleGrant(ctx context.Context, message *tgbotapi.Message) {
 		} else if args[i] == "-l" && i+1 < len(args) {
 			selector = args[i+1]
 			i++
+		} else if args[i] == "--name" && i+1 < len(args) {
+			names = append(names, strings.Split(args[i+1], ",")...)
+			i++
+		} else if args[i] == "--for" && i+1 < len(args) {
+			grantDuration, err := parseGrantDuration(args[i+1])
+			if err != nil {
+				b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ %v", err))
+				return
+			}
+			expires := time.Now().Add(grantDuration)
+			expiresAt = &expires
+			i++
 		}
 	}
 
 	// Grant permission
-	err = b.rbac.GrantPermission(ctx, targetUserID, namespace, resource, verb, selector)
+	err = b.rbac.GrantPermission(ctx, targetUserID, namespace, resource, verb, selector, names, expiresAt)
 	if err != nil {
 		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
 		return
@@ -475,10 +1165,18 @@ func (b *Bot) handleGrant(ctx context.Context, message *tgbotapi.Message) {
 		"Verb: %s\n",
 		targetUserID, namespace, resource, verb)
 
+	if len(names) > 0 {
+		response += fmt.Sprintf("Names: %s\n", strings.Join(names, ", "))
+	}
+
 	if selector != "" {
 		response += fmt.Sprintf("Selector: %s\n", selector)
 	}
 
+	if expiresAt != nil {
+		response += fmt.Sprintf("Expires: %s (in %s)\n", expiresAt.UTC().Format(time.RFC3339), duration.HumanDuration(time.Until(*expiresAt)))
+	}
+
 	b.sendMessage(message.Chat.ID, response)
 }
 
@@ -534,6 +1232,158 @@ func (b *Bot) handleRevoke(ctx context.Context, message *tgbotapi.Message) {
 	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Permission revoked from user `%d`", targetUserID))
 }
 
+// handleSetRole handles the /setrole command (admin only)
+func (b *Bot) handleSetRole(ctx context.Context, message *tgbotapi.Message) {
+	userID := message.From.ID
+	args := strings.Fields(message.CommandArguments())
+
+	// Check if user is admin
+	if !b.rbac.IsBootstrapAdmin(userID) {
+		permission, err := b.rbac.GetUserPermission(ctx, userID)
+		if err != nil || permission.Spec.Role != "admin" {
+			b.sendMessage(message.Chat.ID, "❌ Admin access required")
+			return
+		}
+	}
+
+	if len(args) < 2 {
+		b.sendMessage(message.Chat.ID, "Usage: /setrole <user_id> <viewer|operator|admin>")
+		return
+	}
+
+	targetUserID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Invalid user ID")
+		return
+	}
+
+	role := strings.ToLower(args[1])
+	if !rbac.IsRole(role) {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Unknown role '%s' (expected viewer, operator or admin)", args[1]))
+		return
+	}
+
+	if err := b.rbac.SetRole(ctx, targetUserID, role); err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Role of user `%d` set to *%s*\n\n%s", targetUserID, role, describeRole(role)))
+}
+
+// describeRole explains what a role grants
+func describeRole(role string) string {
+	if role == rbac.RoleAdmin {
+		return "Admins have full access to all namespaces and admin commands."
+	}
+
+	verbs, _ := rbac.RoleVerbs(role)
+	return fmt.Sprintf("Grants %s on all resources in the namespaces the user has permissions in.", strings.Join(verbs, ", "))
+}
+
+// handleTeam handles the /team command (admin only)
+func (b *Bot) handleTeam(ctx context.Context, message *tgbotapi.Message) {
+	userID := message.From.ID
+	args := strings.Fields(message.CommandArguments())
+
+	// Check if user is admin
+	if !b.rbac.IsBootstrapAdmin(userID) {
+		permission, err := b.rbac.GetUserPermission(ctx, userID)
+		if err != nil || permission.Spec.Role != "admin" {
+			b.sendMessage(message.Chat.ID, "❌ Admin access required")
+			return
+		}
+	}
+
+	usage := "Usage:\n" +
+		"/team create <name>\n" +
+		"/team add <name> <user_id>\n" +
+		"/team remove <name> <user_id>\n" +
+		"/team show [name]"
+
+	if len(args) == 0 {
+		b.sendMessage(message.Chat.ID, usage)
+		return
+	}
+
+	switch args[0] {
+	case "create":
+		if len(args) < 2 {
+			b.sendMessage(message.Chat.ID, usage)
+			return
+		}
+		if err := b.rbac.CreateTeam(ctx, args[1]); err != nil {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
+			return
+		}
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Team `%s` created\n\n"+
+			"Add members with /team add and permissions with `kubectl edit telegrambotteam %s`.", args[1], args[1]))
+
+	case "add", "remove":
+		if len(args) < 3 {
+			b.sendMessage(message.Chat.ID, usage)
+			return
+		}
+		memberID, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			b.sendMessage(message.Chat.ID, "❌ Invalid user ID")
+			return
+		}
+
+		if args[0] == "add" {
+			err = b.rbac.AddTeamMember(ctx, args[1], memberID)
+		} else {
+			err = b.rbac.RemoveTeamMember(ctx, args[1], memberID)
+		}
+		if err != nil {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
+			return
+		}
+
+		if args[0] == "add" {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ User `%d` added to team `%s`", memberID, args[1]))
+		} else {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ User `%d` removed from team `%s`", memberID, args[1]))
+		}
+
+	case "show":
+		if len(args) < 2 {
+			b.showTeams(ctx, message.Chat.ID)
+			return
+		}
+		summary, err := b.rbac.GetTeamSummary(ctx, args[1])
+		if err != nil {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
+			return
+		}
+		b.sendCodeBlock(message.Chat.ID, "*Team Summary:*\n", summary, "team.txt")
+
+	default:
+		b.sendMessage(message.Chat.ID, usage)
+	}
+}
+
+// showTeams lists all teams with their member counts
+func (b *Bot) showTeams(ctx context.Context, chatID int64) {
+	teams, err := b.rbac.ListTeams(ctx)
+	if err != nil {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Error: %v", err))
+		return
+	}
+
+	if len(teams) == 0 {
+		b.sendMessage(chatID, "No teams found")
+		return
+	}
+
+	response := "*Teams:*\n\n"
+	for _, team := range teams {
+		response += fmt.Sprintf("• `%s` - %d members, %d permissions\n", team.Name, len(team.Spec.Members), len(team.Spec.Permissions))
+	}
+
+	b.sendMessage(chatID, response)
+}
+
 // handlePermissions handles the /permissions command
 func (b *Bot) handlePermissions(ctx context.Context, message *tgbotapi.Message) {
 	userID := message.From.ID
@@ -564,7 +1414,7 @@ func (b *Bot) handlePermissions(ctx context.Context, message *tgbotapi.Message)
 		return
 	}
 
-	b.sendMessage(message.Chat.ID, fmt.Sprintf("*Permissions Summary:*\n\n```\n%s\n```", summary))
+	b.sendCodeBlock(message.Chat.ID, "*Permissions Summary:*\n", summary, "permissions.txt")
 }
 
 // handleSelfUpdate handles the /selfupdate command (admin only)
@@ -583,17 +1433,428 @@ func (b *Bot) handleSelfUpdate(ctx context.Context, message *tgbotapi.Message) {
 	namespace := b.config.BotNamespace
 	deploymentName := b.config.BotDeploymentName
 
-	b.sendMessage(message.Chat.ID, fmt.Sprintf("🔄 Initiating self-update...\n\n"+
-		"Namespace: `%s`\n"+
-		"Deployment: `%s`\n\n"+
-		"Restarting to pull latest image...", namespace, deploymentName))
-
-	// Restart the bot's own deployment
-	err := b.k8sClient.RestartDeployment(ctx, namespace, deploymentName)
+	deployment, err := b.k8sClient.GetDeployment(ctx, namespace, deploymentName)
 	if err != nil {
 		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
 		return
 	}
 
-	b.sendMessage(message.Chat.ID, "✅ Self-update triggered! The bot will restart shortly and pull the latest image from the registry.")
+	images := []string{}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		images = append(images, container.Image)
+	}
+
+	summary := fmt.Sprintf("🔄 *Self-update*?\n\n"+
+		"Namespace: `%s`\n"+
+		"Deployment: `%s`\n"+
+		"Image: `%s`\n\n"+
+		"The bot restarts to pull the latest image.", namespace, deploymentName, strings.Join(images, "`, `"))
+
+	b.requestConfirmation(message.Chat.ID, userID, summary, func(ctx context.Context) string {
+		// The user may have lost the admin role since the confirmation was shown
+		if !b.isAdmin(ctx, userID) {
+			return "❌ Admin access required"
+		}
+
+		// Restart the bot's own deployment
+		if err := b.k8sClient.RestartDeployment(ctx, namespace, deploymentName); err != nil {
+			return fmt.Sprintf("❌ Error: %v", err)
+		}
+
+		return "✅ Self-update triggered! The bot will restart shortly and pull the latest image from the registry."
+	})
+}
+
+// workloadAliases maps the kind prefix of a "kind/name" argument to its resource
+var workloadAliases = map[string]string{
+	"deploy":       "deployments",
+	"deployment":   "deployments",
+	"deployments":  "deployments",
+	"sts":          "statefulsets",
+	"statefulset":  "statefulsets",
+	"statefulsets": "statefulsets",
+	"ds":           "daemonsets",
+	"daemonset":    "daemonsets",
+	"daemonsets":   "daemonsets",
+}
+
+// workloadKinds maps workload resources to their display names
+var workloadKinds = map[string]string{
+	"deployments":  "Deployment",
+	"statefulsets": "StatefulSet",
+	"daemonsets":   "DaemonSet",
+}
+
+// maxGrantDuration caps how long a time-bound grant may last
+const maxGrantDuration = 90 * 24 * time.Hour
+
+// parseGrantDuration parses the --for value of /grant. Besides Go durations
+// such as 8h or 90m it accepts whole days such as 7d.
+func parseGrantDuration(value string) (time.Duration, error) {
+	var grantDuration time.Duration
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s': use e.g. 8h, 90m or 7d", value)
+		}
+		grantDuration = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		grantDuration, err = time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s': use e.g. 8h, 90m or 7d", value)
+		}
+	}
+
+	if grantDuration <= 0 {
+		return 0, fmt.Errorf("duration must be positive")
+	}
+	if grantDuration > maxGrantDuration {
+		return 0, fmt.Errorf("duration must be at most %s", duration.HumanDuration(maxGrantDuration))
+	}
+
+	return grantDuration, nil
+}
+
+// parseWorkloadRef splits a "kind/name" argument into resource and name.
+// A bare name refers to a deployment.
+func parseWorkloadRef(ref string) (string, string, error) {
+	kind, name, found := strings.Cut(ref, "/")
+	if !found {
+		return "deployments", ref, nil
+	}
+
+	resource, ok := workloadAliases[strings.ToLower(kind)]
+	if !ok {
+		return "", "", fmt.Errorf("unsupported workload kind: %s", kind)
+	}
+	if name == "" {
+		return "", "", fmt.Errorf("missing workload name in '%s'", ref)
+	}
+
+	return resource, name, nil
+}
+
+// maxEvents is the number of most recent events shown by /events
+const maxEvents = 20
+
+// objectKinds maps lowercase kind names and short names to Kubernetes kinds
+var objectKinds = map[string]string{
+	"po":          "Pod",
+	"pod":         "Pod",
+	"pods":        "Pod",
+	"deploy":      "Deployment",
+	"deployment":  "Deployment",
+	"deployments": "Deployment",
+	"rs":          "ReplicaSet",
+	"replicaset":  "ReplicaSet",
+	"sts":         "StatefulSet",
+	"statefulset": "StatefulSet",
+	"ds":          "DaemonSet",
+	"daemonset":   "DaemonSet",
+	"job":         "Job",
+	"jobs":        "Job",
+	"cj":          "CronJob",
+	"cronjob":     "CronJob",
+	"svc":         "Service",
+	"service":     "Service",
+	"node":        "Node",
+	"no":          "Node",
+	"pvc":         "PersistentVolumeClaim",
+	"hpa":         "HorizontalPodAutoscaler",
+}
+
+// parseObjectRef splits a "kind/name" argument into Kubernetes kind and name
+func parseObjectRef(ref string) (string, string, error) {
+	kind, name, found := strings.Cut(ref, "/")
+	if !found || kind == "" || name == "" {
+		return "", "", fmt.Errorf("invalid object reference '%s', expected <kind>/<name>", ref)
+	}
+
+	if k, ok := objectKinds[strings.ToLower(kind)]; ok {
+		return k, name, nil
+	}
+
+	// Assume an exact kind name was given (e.g., "Ingress")
+	return strings.ToUpper(kind[:1]) + kind[1:], name, nil
+}
+
+// formatPodSummary formats a pod summary as a list entry
+func formatPodSummary(summary k8s.PodSummary) string {
+	return fmt.Sprintf("📦 `%s`\n   Status: %s  Ready: %s\n   Restarts: %d  Age: %s\n   Node: %s\n\n",
+		summary.Name, summary.Status, summary.Ready(), summary.Restarts, summary.Age, summary.Node)
+}
+
+// listArgs holds the arguments shared by list commands
+type listArgs struct {
+	namespace     string
+	selector      string
+	allNamespaces bool
+}
+
+// parseListArgs parses "[namespace] [-l <selector>] [-A]" list command arguments
+func parseListArgs(args []string) listArgs {
+	opts := listArgs{namespace: "default"}
+
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-l" && i+1 < len(args):
+			opts.selector = args[i+1]
+			i++
+		case args[i] == "-n" && i+1 < len(args):
+			opts.namespace = args[i+1]
+			i++
+		case args[i] == "-A" || args[i] == "--all-namespaces":
+			opts.allNamespaces = true
+		case !strings.HasPrefix(args[i], "-"):
+			opts.namespace = args[i]
+		}
+	}
+
+	opts.namespace = rbac.NormalizeNamespace(opts.namespace)
+	return opts
+}
+
+// listNamespace returns the namespace to pass to the Kubernetes list call
+func (o listArgs) listNamespace() string {
+	if o.allNamespaces {
+		return ""
+	}
+	return o.namespace
+}
+
+// scope describes where the list command looked. The namespace and selector are
+// put in code spans, so the result must not be placed inside bold text.
+func (o listArgs) scope() string {
+	scope := "namespace " + codeSpan(o.namespace)
+	if o.allNamespaces {
+		scope = "all namespaces"
+	}
+	if o.selector != "" {
+		scope += " matching " + codeSpan(o.selector)
+	}
+	return scope
+}
+
+// displayName prefixes object names with their namespace when listing all namespaces
+func (o listArgs) displayName(namespace, name string) string {
+	if o.allNamespaces {
+		return namespace + "/" + name
+	}
+	return name
+}
+
+// formatServicePort formats a service port like "http 80→8080/TCP (nodePort 30080)"
+func formatServicePort(name string, port int32, targetPort, protocol string, nodePort int32) string {
+	result := fmt.Sprintf("%d→%s/%s", port, targetPort, protocol)
+	if name != "" {
+		result = name + " " + result
+	}
+	if nodePort != 0 {
+		result += fmt.Sprintf(" (nodePort %d)", nodePort)
+	}
+	return result
+}
+
+// defaultLogTail is the number of log lines returned when --tail is not given
+const defaultLogTail = 100
+
+// filteredLogTail is the number of log lines --grep and --level search when --tail
+// is not given. The API server has no byte limit that keeps the end of a log, so a
+// line count bounds the transfer.
+const filteredLogTail = 20000
+
+// maxAggregatedPods is the largest number of pods whose logs are merged
+const maxAggregatedPods = 50
+
+// maxReportedLogErrors is the number of failed pods listed in an aggregated log reply
+const maxReportedLogErrors = 5
+
+// containerChoiceTTL is how long the container buttons of /logs stay usable
+const containerChoiceTTL = 10 * time.Minute
+
+// logsArgs holds the parsed arguments of the /logs command
+type logsArgs struct {
+	podName      string
+	namespace    string
+	options      k8s.LogOptions
+	followFor    time.Duration
+	selector     string // Aggregate logs of pods matching this selector
+	workload     string // Aggregate logs of this workload's pods (e.g., "deployments")
+	workloadName string
+	filter       k8s.LogFilter
+}
+
+// parseLogsArgs parses "<pod|deploy/<name>> [-l <selector>] [-n <namespace>] [-c <container>] [--previous]
+// [--since <duration|time>] [--tail <lines>] [-f [--for <duration>]] [--grep <regex>] [--invert] [--level error|warn]"
+func parseLogsArgs(args []string) (logsArgs, error) {
+	req := logsArgs{
+		namespace: "default",
+		options:   k8s.LogOptions{TailLines: defaultLogTail},
+		followFor: defaultFollowDuration,
+	}
+	tailGiven := false
+
+	for i := 0; i < len(args); i++ {
+		hasValue := i+1 < len(args)
+		switch {
+		case args[i] == "-n" && hasValue:
+			req.namespace = args[i+1]
+			i++
+		case args[i] == "-c" && hasValue:
+			req.options.Container = args[i+1]
+			i++
+		case args[i] == "--previous" || args[i] == "-p":
+			req.options.Previous = true
+		case args[i] == "--since" && hasValue:
+			if d, err := time.ParseDuration(args[i+1]); err == nil && d > 0 {
+				// Round up: SinceSeconds 0 would mean no limit at all
+				req.options.SinceSeconds = int64((d + time.Second - 1) / time.Second)
+			} else if t, err := time.Parse(time.RFC3339, args[i+1]); err == nil {
+				req.options.SinceTime = &t
+			} else {
+				return logsArgs{}, fmt.Errorf("invalid --since value '%s' (use a duration like 10m or an RFC3339 time)", args[i+1])
+			}
+			i++
+		case args[i] == "--tail" && hasValue:
+			tail, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || tail <= 0 {
+				return logsArgs{}, fmt.Errorf("invalid --tail value '%s'", args[i+1])
+			}
+			req.options.TailLines = tail
+			tailGiven = true
+			i++
+		case args[i] == "-f" || args[i] == "--follow":
+			req.options.Follow = true
+		case args[i] == "--for" && hasValue:
+			d, err := time.ParseDuration(args[i+1])
+			if err != nil || d <= 0 || d > maxFollowDuration {
+				return logsArgs{}, fmt.Errorf("invalid --for value '%s' (use a duration up to %s)", args[i+1], maxFollowDuration)
+			}
+			req.followFor = d
+			i++
+		case args[i] == "-l" && hasValue:
+			req.selector = args[i+1]
+			i++
+		case args[i] == "--grep" && hasValue:
+			pattern, err := regexp.Compile(args[i+1])
+			if err != nil {
+				return logsArgs{}, fmt.Errorf("invalid --grep pattern: %v", err)
+			}
+			req.filter.Pattern = pattern
+			i++
+		case args[i] == "--invert" || args[i] == "-v":
+			req.filter.Invert = true
+		case args[i] == "--level" && hasValue:
+			level, err := k8s.ParseLogLevel(args[i+1])
+			if err != nil {
+				return logsArgs{}, err
+			}
+			req.filter.MinLevel = level
+			i++
+		case strings.HasPrefix(args[i], "-"):
+			return logsArgs{}, fmt.Errorf("unknown or incomplete flag '%s'", args[i])
+		case req.podName != "" || req.workload != "":
+			return logsArgs{}, fmt.Errorf("unexpected argument '%s'", args[i])
+		case strings.Contains(args[i], "/"):
+			kind, name, _ := strings.Cut(args[i], "/")
+			if kind == "pod" || kind == "po" || kind == "pods" {
+				req.podName = name
+				continue
+			}
+
+			resource, name, err := parseWorkloadRef(args[i])
+			if err != nil {
+				return logsArgs{}, err
+			}
+			req.workload = resource
+			req.workloadName = name
+		default:
+			req.podName = args[i]
+		}
+	}
+
+	targets := 0
+	for _, target := range []string{req.podName, req.workloadName, req.selector} {
+		if target != "" {
+			targets++
+		}
+	}
+	if targets == 0 {
+		return logsArgs{}, fmt.Errorf("pod name, workload or -l <selector> is required")
+	}
+	if targets > 1 {
+		return logsArgs{}, fmt.Errorf("use only one of pod name, workload or -l <selector>")
+	}
+	if req.filter.Invert && req.filter.Pattern == nil {
+		return logsArgs{}, fmt.Errorf("--invert requires --grep")
+	}
+	if req.aggregate() && req.options.Follow {
+		return logsArgs{}, fmt.Errorf("following is only supported for a single pod")
+	}
+
+	// Filters search a much wider tail than the default, so --level error finds
+	// errors older than the last lines. What is kept is still bounded by
+	// k8s.DefaultMaxLogBytes.
+	if !req.filter.IsEmpty() && !tailGiven && !req.options.Follow {
+		req.options.TailLines = filteredLogTail
+	}
+
+	req.namespace = rbac.NormalizeNamespace(req.namespace)
+	return req, nil
+}
+
+// aggregate reports whether the logs of several pods are requested
+func (r logsArgs) aggregate() bool {
+	return r.workload != "" || r.selector != ""
+}
+
+// filename returns the name used when the logs are sent as a file
+func (r logsArgs) filename() string {
+	name := r.podName
+	switch {
+	case r.workload != "":
+		name = strings.TrimSuffix(r.workload, "s") + "-" + r.workloadName
+	case r.selector != "":
+		name = "selector"
+	}
+	if r.options.Container != "" {
+		name += "-" + r.options.Container
+	}
+	if r.options.Previous {
+		name += "-previous"
+	}
+	return name + ".log"
+}
+
+// describe summarizes the log selection for message headers. User input is put in
+// code spans, so the result must not be placed inside bold or italic text.
+func (r logsArgs) describe() string {
+	parts := []string{"namespace: " + codeSpan(r.namespace)}
+	switch {
+	case r.workload != "":
+		parts = append([]string{workloadKinds[r.workload] + " " + codeSpan(r.workloadName)}, parts...)
+	case r.selector != "":
+		parts = append([]string{"selector: " + codeSpan(r.selector)}, parts...)
+	}
+	if r.options.Container != "" {
+		parts = append(parts, "container: "+codeSpan(r.options.Container))
+	}
+	if r.options.Previous {
+		parts = append(parts, "previous instance")
+	}
+	if r.filter.Pattern != nil {
+		grep := "grep: " + codeSpan(r.filter.Pattern.String())
+		if r.filter.Invert {
+			grep = "grep -v: " + codeSpan(r.filter.Pattern.String())
+		}
+		parts = append(parts, grep)
+	}
+	switch r.filter.MinLevel {
+	case k8s.LevelError:
+		parts = append(parts, "level: error")
+	case k8s.LevelWarn:
+		parts = append(parts, "level: warn+")
+	}
+	return strings.Join(parts, ", ")
 }
@@ -3,6 +3,12 @@ package bot
 import (
 	"strings"
 	"testing"
+	"time"
+
+	"kubectl-bot/internal/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Test command parsing and argument extraction
@@ -262,3 +268,359 @@ func TestGrantCommandParsing(t *testing.T) {
 		}
 	}
 }
+
+// Test --for duration parsing for /grant
+func TestParseGrantDuration(t *testing.T) {
+	tests := []struct {
+		value       string
+		expected    time.Duration
+		expectError bool
+	}{
+		{"8h", 8 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"0h", 0, true},
+		{"-1h", 0, true},
+		{"91d", 0, true},
+		{"xd", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		result, err := parseGrantDuration(tt.value)
+		if tt.expectError {
+			if err == nil {
+				t.Errorf("parseGrantDuration(%q) expected error, got nil", tt.value)
+			}
+			continue
+		}
+
+		if err != nil || result != tt.expected {
+			t.Errorf("parseGrantDuration(%q) = %v, %v, expected %v", tt.value, result, err, tt.expected)
+		}
+	}
+}
+
+// Test workload reference parsing for /restart and /scale
+func TestParseWorkloadRef(t *testing.T) {
+	tests := []struct {
+		ref              string
+		expectedResource string
+		expectedName     string
+		expectError      bool
+	}{
+		{"api-deployment", "deployments", "api-deployment", false},
+		{"deploy/api", "deployments", "api", false},
+		{"sts/postgres", "statefulsets", "postgres", false},
+		{"StatefulSet/redis", "statefulsets", "redis", false},
+		{"ds/fluent-bit", "daemonsets", "fluent-bit", false},
+		{"cronjob/nightly", "", "", true},
+		{"sts/", "", "", true},
+	}
+
+	for _, tt := range tests {
+		resource, name, err := parseWorkloadRef(tt.ref)
+		if tt.expectError {
+			if err == nil {
+				t.Errorf("parseWorkloadRef(%q) expected error, got nil", tt.ref)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("parseWorkloadRef(%q) unexpected error: %v", tt.ref, err)
+			continue
+		}
+		if resource != tt.expectedResource || name != tt.expectedName {
+			t.Errorf("parseWorkloadRef(%q) = (%q, %q), expected (%q, %q)",
+				tt.ref, resource, name, tt.expectedResource, tt.expectedName)
+		}
+	}
+}
+
+// Test object reference parsing for /events -o
+func TestParseObjectRef(t *testing.T) {
+	tests := []struct {
+		ref          string
+		expectedKind string
+		expectedName string
+		expectError  bool
+	}{
+		{"pod/api-7d9f", "Pod", "api-7d9f", false},
+		{"deploy/api", "Deployment", "api", false},
+		{"Ingress/web", "Ingress", "web", false},
+		{"ingress/web", "Ingress", "web", false},
+		{"api-7d9f", "", "", true},
+		{"pod/", "", "", true},
+	}
+
+	for _, tt := range tests {
+		kind, name, err := parseObjectRef(tt.ref)
+		if tt.expectError {
+			if err == nil {
+				t.Errorf("parseObjectRef(%q) expected error, got nil", tt.ref)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("parseObjectRef(%q) unexpected error: %v", tt.ref, err)
+			continue
+		}
+		if kind != tt.expectedKind || name != tt.expectedName {
+			t.Errorf("parseObjectRef(%q) = (%q, %q), expected (%q, %q)",
+				tt.ref, kind, name, tt.expectedKind, tt.expectedName)
+		}
+	}
+}
+
+// Test event formatting for /events
+func TestFormatEvent(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := &corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "api-7d9f"},
+		Reason:         "Failed_Pull",
+		Message:        "Failed to pull image `registry/api_server:*`",
+		Type:           "Warning",
+		Count:          3,
+		LastTimestamp:  metav1.NewTime(now.Add(-5 * time.Minute)),
+	}
+
+	text := formatEvent(event, now)
+	expected := "⚠️ 5m ago `Pod/api-7d9f` Failed\\_Pull (x3)\n   Failed to pull image \\`registry/api\\_server:\\*\\`\n\n"
+	if text != expected {
+		t.Errorf("formatEvent() = %q, expected %q", text, expected)
+	}
+}
+
+// Test list command flag parsing
+func TestParseListArgs(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected listArgs
+		scope    string
+	}{
+		{[]string{}, listArgs{namespace: "default"}, "namespace `default`"},
+		{[]string{"production"}, listArgs{namespace: "production"}, "namespace `production`"},
+		{[]string{"production", "-l", "app=frontend"},
+			listArgs{namespace: "production", selector: "app=frontend"}, "namespace `production` matching `app=frontend`"},
+		{[]string{"-A"}, listArgs{namespace: "default", allNamespaces: true}, "all namespaces"},
+		{[]string{"-l", "tier=web", "-A"},
+			listArgs{namespace: "default", selector: "tier=web", allNamespaces: true}, "all namespaces matching `tier=web`"},
+		{[]string{"-n", "staging"}, listArgs{namespace: "staging"}, "namespace `staging`"},
+	}
+
+	for _, tt := range tests {
+		result := parseListArgs(tt.args)
+		if result != tt.expected {
+			t.Errorf("parseListArgs(%v) = %+v, expected %+v", tt.args, result, tt.expected)
+		}
+		if result.scope() != tt.scope {
+			t.Errorf("parseListArgs(%v).scope() = %q, expected %q", tt.args, result.scope(), tt.scope)
+		}
+	}
+
+	all := listArgs{namespace: "default", allNamespaces: true}
+	if all.listNamespace() != "" || all.displayName("prod", "api") != "prod/api" {
+		t.Error("All-namespace listing should query every namespace and prefix names")
+	}
+	single := listArgs{namespace: "prod"}
+	if single.listNamespace() != "prod" || single.displayName("prod", "api") != "api" {
+		t.Error("Single-namespace listing should query one namespace and keep bare names")
+	}
+}
+
+// Test service port formatting for /service
+func TestFormatServicePort(t *testing.T) {
+	tests := []struct {
+		name       string
+		port       int32
+		targetPort string
+		protocol   string
+		nodePort   int32
+		expected   string
+	}{
+		{"http", 80, "8080", "TCP", 0, "http 80→8080/TCP"},
+		{"", 53, "53", "UDP", 0, "53→53/UDP"},
+		{"web", 443, "https", "TCP", 30443, "web 443→https/TCP (nodePort 30443)"},
+	}
+
+	for _, tt := range tests {
+		result := formatServicePort(tt.name, tt.port, tt.targetPort, tt.protocol, tt.nodePort)
+		if result != tt.expected {
+			t.Errorf("formatServicePort(%q, %d, %q, %q, %d) = %q, expected %q",
+				tt.name, tt.port, tt.targetPort, tt.protocol, tt.nodePort, result, tt.expected)
+		}
+	}
+}
+
+// Test /logs flag parsing
+func TestParseLogsArgs(t *testing.T) {
+	req, err := parseLogsArgs([]string{"api-7d9f", "-n", "production", "-c", "sidecar", "--previous", "--since", "10m", "--tail", "50"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if req.podName != "api-7d9f" || req.namespace != "production" {
+		t.Errorf("Pod/namespace = %q/%q, expected api-7d9f/production", req.podName, req.namespace)
+	}
+	if req.options.Container != "sidecar" || !req.options.Previous {
+		t.Errorf("Container/previous = %q/%v, expected sidecar/true", req.options.Container, req.options.Previous)
+	}
+	if req.options.SinceSeconds != 600 || req.options.TailLines != 50 {
+		t.Errorf("Since/tail = %d/%d, expected 600/50", req.options.SinceSeconds, req.options.TailLines)
+	}
+	if req.filename() != "api-7d9f-sidecar-previous.log" {
+		t.Errorf("Unexpected filename: %q", req.filename())
+	}
+	if req.describe() != "namespace: `production`, container: `sidecar`, previous instance" {
+		t.Errorf("Unexpected description: %q", req.describe())
+	}
+
+	req, err = parseLogsArgs([]string{"api-7d9f", "--since", "2024-01-01T10:00:00Z"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if req.options.SinceTime == nil || req.options.SinceTime.Hour() != 10 {
+		t.Errorf("Expected RFC3339 --since to set SinceTime, got %v", req.options.SinceTime)
+	}
+	if req.namespace != "default" || req.options.TailLines != defaultLogTail {
+		t.Errorf("Expected defaults, got namespace %q tail %d", req.namespace, req.options.TailLines)
+	}
+
+	// Sub-second windows round up instead of becoming "no limit"
+	req, err = parseLogsArgs([]string{"api-7d9f", "--since", "500ms"})
+	if err != nil || req.options.SinceSeconds != 1 {
+		t.Errorf("Expected --since 500ms to limit to 1 second, got %d (%v)", req.options.SinceSeconds, err)
+	}
+
+	invalid := [][]string{
+		{"-n", "production"},
+		{"api", "--since", "yesterday"},
+		{"api", "--tail", "-5"},
+		{"api", "--watch"},
+		{"api", "-f", "--for", "2h"},
+	}
+	for _, args := range invalid {
+		if _, err := parseLogsArgs(args); err == nil {
+			t.Errorf("parseLogsArgs(%v) expected error, got nil", args)
+		}
+	}
+}
+
+// Test /logs follow flag parsing
+func TestParseLogsArgs_Follow(t *testing.T) {
+	req, err := parseLogsArgs([]string{"api-7d9f", "-f"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !req.options.Follow || req.followFor != defaultFollowDuration {
+		t.Errorf("Follow/for = %v/%s, expected true/%s", req.options.Follow, req.followFor, defaultFollowDuration)
+	}
+
+	req, err = parseLogsArgs([]string{"api-7d9f", "--follow", "--for", "5m"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if req.followFor != 5*time.Minute {
+		t.Errorf("Follow duration = %s, expected 5m", req.followFor)
+	}
+}
+
+// Test /logs workload and selector targets
+func TestParseLogsArgs_Aggregate(t *testing.T) {
+	req, err := parseLogsArgs([]string{"deploy/api", "-n", "production"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !req.aggregate() || req.workload != "deployments" || req.workloadName != "api" {
+		t.Errorf("Expected deployment target, got %+v", req)
+	}
+	if req.filename() != "deployment-api.log" {
+		t.Errorf("Unexpected filename: %q", req.filename())
+	}
+
+	req, err = parseLogsArgs([]string{"-l", "app=my_api", "--grep", "err.*"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !req.aggregate() || req.selector != "app=my_api" {
+		t.Errorf("Expected selector target, got %+v", req)
+	}
+	if req.describe() != "selector: `app=my_api`, namespace: `default`, grep: `err.*`" {
+		t.Errorf("Unexpected description: %q", req.describe())
+	}
+
+	req, err = parseLogsArgs([]string{"pod/api-7d9f"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if req.aggregate() || req.podName != "api-7d9f" {
+		t.Errorf("Expected single pod target, got %+v", req)
+	}
+
+	invalid := [][]string{
+		{"api-7d9f", "-l", "app=api"},
+		{"deploy/api", "-f"},
+		{"api-7d9f", "extra"},
+		{"cronjob/nightly"},
+	}
+	for _, args := range invalid {
+		if _, err := parseLogsArgs(args); err == nil {
+			t.Errorf("parseLogsArgs(%v) expected error, got nil", args)
+		}
+	}
+}
+
+// Test /logs filter flag parsing
+func TestParseLogsArgs_Filter(t *testing.T) {
+	req, err := parseLogsArgs([]string{"api-7d9f", "--grep", "time(out)?", "--invert", "--level", "warn"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if req.filter.Pattern == nil || req.filter.Pattern.String() != "time(out)?" || !req.filter.Invert {
+		t.Errorf("Unexpected grep filter: %+v", req.filter)
+	}
+	if req.filter.MinLevel != k8s.LevelWarn {
+		t.Errorf("MinLevel = %d, expected %d", req.filter.MinLevel, k8s.LevelWarn)
+	}
+	if !strings.Contains(req.describe(), "grep -v: `time(out)?`") || !strings.Contains(req.describe(), "level: warn+") {
+		t.Errorf("Description should mention filters: %q", req.describe())
+	}
+
+	// Filters search a wider tail unless --tail is given
+	if req.options.TailLines != filteredLogTail {
+		t.Errorf("TailLines = %d, expected %d with a filter", req.options.TailLines, filteredLogTail)
+	}
+	req, err = parseLogsArgs([]string{"api-7d9f", "--level", "error", "--tail", "500"})
+	if err != nil || req.options.TailLines != 500 {
+		t.Errorf("Expected explicit --tail to be kept with a filter, got %d (%v)", req.options.TailLines, err)
+	}
+	req, err = parseLogsArgs([]string{"api-7d9f", "--level", "error", "-f"})
+	if err != nil || req.options.TailLines != defaultLogTail {
+		t.Errorf("Expected following to keep the default tail, got %d (%v)", req.options.TailLines, err)
+	}
+
+	invalid := [][]string{
+		{"api", "--grep", "("},
+		{"api", "--level", "debug"},
+		{"api", "--invert"},
+	}
+	for _, args := range invalid {
+		if _, err := parseLogsArgs(args); err == nil {
+			t.Errorf("parseLogsArgs(%v) expected error, got nil", args)
+		}
+	}
+}
+
+func TestDescribeRole(t *testing.T) {
+	if text := describeRole("operator"); !strings.Contains(text, "restart") || !strings.Contains(text, "namespaces the user has permissions in") {
+		t.Errorf("describeRole(operator) = %q", text)
+	}
+	if text := describeRole("viewer"); strings.Contains(text, "restart") {
+		t.Errorf("describeRole(viewer) = %q, expected no write verbs", text)
+	}
+	if text := describeRole("admin"); !strings.Contains(text, "full access") {
+		t.Errorf("describeRole(admin) = %q", text)
+	}
+}
@@ -0,0 +1,177 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// maxMessageLength keeps messages safely below Telegram's 4096 character limit
+	maxMessageLength = 4000
+
+	// maxCaptionLength is Telegram's limit for document captions
+	maxCaptionLength = 1024
+
+	// previewLines is the number of trailing lines shown in the caption of a file reply
+	previewLines = 10
+)
+
+// All outgoing text and files pass through the helpers below, which redact
+// secrets and note the number of redactions in a footer.
+
+// sendMessage sends a text message to a chat, falling back to a file when it is too long
+func (b *Bot) sendMessage(chatID int64, text string) {
+	text, redactions := b.redactor.redact(text)
+	text += redactionFooter(redactions)
+
+	if utf8.RuneCountInString(text) > maxMessageLength {
+		b.deliverDocument(chatID, "output.txt", text, "📄 Output too long for a message, sent as a file")
+		return
+	}
+
+	b.deliverMessage(chatID, text)
+}
+
+// sendMessageWithKeyboard sends a text message with an inline keyboard to a chat
+// and returns the ID of the sent message (0 if sending failed)
+func (b *Bot) sendMessageWithKeyboard(chatID int64, text string, keyboard tgbotapi.InlineKeyboardMarkup) int {
+	text, redactions := b.redactor.redact(text)
+
+	msg := tgbotapi.NewMessage(chatID, text+redactionFooter(redactions))
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	sent, err := b.api.Send(msg)
+	if err != nil {
+		log.Printf("Failed to send message: %v", err)
+		return 0
+	}
+	return sent.MessageID
+}
+
+// editMessage replaces the text of a sent message. A nil keyboard removes the inline keyboard.
+func (b *Bot) editMessage(chatID int64, messageID int, text string, keyboard *tgbotapi.InlineKeyboardMarkup) {
+	text, redactions := b.redactor.redact(text)
+
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text+redactionFooter(redactions))
+	edit.ParseMode = "Markdown"
+	edit.ReplyMarkup = keyboard
+	_, err := b.api.Send(edit)
+	if err != nil {
+		log.Printf("Failed to edit message: %v", err)
+	}
+}
+
+// sendCodeBlock sends a title followed by body in a code block. When the result does
+// not fit in a message, body is uploaded as a file named filename and the caption
+// shows the title with a preview of the last lines.
+func (b *Bot) sendCodeBlock(chatID int64, title, body, filename string) {
+	title, titleRedactions := b.redactor.redact(title)
+	body, bodyRedactions := b.redactor.redact(body)
+	footer := redactionFooter(titleRedactions + bodyRedactions)
+
+	text := codeBlock(title, body) + footer
+	if utf8.RuneCountInString(text) <= maxMessageLength {
+		b.deliverMessage(chatID, text)
+		return
+	}
+
+	b.deliverDocument(chatID, filename, body, documentCaption(title, body, footer))
+}
+
+// sendDocument uploads content as a file with a Markdown caption
+func (b *Bot) sendDocument(chatID int64, filename, content, caption string) {
+	content, contentRedactions := b.redactor.redact(content)
+	caption, captionRedactions := b.redactor.redact(caption)
+
+	b.deliverDocument(chatID, filename, content, caption+redactionFooter(contentRedactions+captionRedactions))
+}
+
+// deliverMessage sends already redacted text as a Markdown message
+func (b *Bot) deliverMessage(chatID int64, text string) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	_, err := b.api.Send(msg)
+	if err != nil {
+		log.Printf("Failed to send message: %v", err)
+	}
+}
+
+// deliverDocument uploads already redacted content as a file
+func (b *Bot) deliverDocument(chatID int64, filename, content, caption string) {
+	caption = truncateCaption(caption)
+
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: filename, Bytes: []byte(content)})
+	doc.Caption = caption
+	doc.ParseMode = "Markdown"
+	_, err := b.api.Send(doc)
+	if err != nil {
+		log.Printf("Failed to send document: %v", err)
+	}
+}
+
+// documentCaption builds the caption of a file sent by sendCodeBlock: the title,
+// the last lines of body in a code block and the footer. The preview is sized to
+// fit the caption limit before the code block markers are added, so the caption is
+// never cut inside the code block; without room for it there is no preview.
+func documentCaption(title, body, footer string) string {
+	const fenceOpen, fenceClose = "\n```\n", "\n```"
+
+	room := maxCaptionLength - utf8.RuneCountInString(title+footer) - len(fenceOpen+fenceClose)
+	if preview := tailPreview(body, previewLines, room); preview != "" {
+		return title + fenceOpen + preview + fenceClose + footer
+	}
+	return title + footer
+}
+
+// truncateCaption cuts a caption that is too long for Telegram at the last line
+// break that fits, so lines of Markdown are either kept whole or dropped
+func truncateCaption(caption string) string {
+	runes := []rune(caption)
+	if len(runes) <= maxCaptionLength {
+		return caption
+	}
+
+	cut := string(runes[:maxCaptionLength-4])
+	if i := strings.LastIndex(cut, "\n"); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "\n..."
+}
+
+// codeBlock renders a title followed by body in a Markdown code block. Backticks
+// in body are replaced so they cannot close the code block early; the file
+// fallback of sendCodeBlock keeps the original text.
+func codeBlock(title, body string) string {
+	return fmt.Sprintf("%s\n```\n%s\n```", title, strings.ReplaceAll(body, "`", "'"))
+}
+
+// codeSpan puts text such as an API error in an inline Markdown code span, so
+// characters like _ and * in it are shown as they are
+func codeSpan(text string) string {
+	return "`" + strings.ReplaceAll(text, "`", "'") + "`"
+}
+
+// tailPreview returns up to maxLines trailing lines of text, cut at a rune
+// boundary to at most maxLen characters. Backticks are replaced so the preview
+// cannot close the surrounding Markdown code block.
+func tailPreview(text string, maxLines, maxLen int) string {
+	if maxLines <= 0 || maxLen <= 0 {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+
+	preview := strings.ReplaceAll(strings.Join(lines, "\n"), "`", "'")
+	if runes := []rune(preview); len(runes) > maxLen {
+		preview = string(runes[len(runes)-maxLen:])
+	}
+
+	return preview
+}
@@ -0,0 +1,92 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTailPreview(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxLines int
+		maxLen   int
+		expected string
+	}{
+		{"a\nb\nc\nd\n", 2, 100, "c\nd"},
+		{"a\nb", 5, 100, "a\nb"},
+		{"line with `code`", 1, 100, "line with 'code'"},
+		{"abcdef", 1, 3, "def"},
+		{"héllo wörld", 1, 5, "wörld"},
+		{"anything", 0, 100, ""},
+	}
+
+	for _, tt := range tests {
+		result := tailPreview(tt.text, tt.maxLines, tt.maxLen)
+		if result != tt.expected {
+			t.Errorf("tailPreview(%q, %d, %d) = %q, expected %q", tt.text, tt.maxLines, tt.maxLen, result, tt.expected)
+		}
+	}
+}
+
+func TestCodeBlock(t *testing.T) {
+	result := codeBlock("*Logs:*", "run `make`\n```\ndone")
+	expected := "*Logs:*\n```\nrun 'make'\n'''\ndone\n```"
+	if result != expected {
+		t.Errorf("codeBlock() = %q, expected %q", result, expected)
+	}
+}
+
+func TestDocumentCaption(t *testing.T) {
+	body := strings.Repeat("log line with some text 🚀\n", 100)
+
+	for _, titleLength := range []int{10, 500, 1000, 1100} {
+		title := strings.Repeat("x", titleLength)
+		caption := documentCaption(title, body, "\n\n🔒 2 secrets redacted")
+		if titleLength <= 1000 && utf8.RuneCountInString(caption) > maxCaptionLength {
+			t.Errorf("title of %d runes: caption has %d runes, expected at most %d", titleLength, utf8.RuneCountInString(caption), maxCaptionLength)
+		}
+		if strings.Count(caption, "```")%2 != 0 {
+			t.Errorf("title of %d runes: caption has an unclosed code block: %q", titleLength, caption)
+		}
+	}
+
+	if caption := documentCaption("*Logs:*", "a\nb", ""); caption != "*Logs:*\n```\na\nb\n```" {
+		t.Errorf("documentCaption() = %q", caption)
+	}
+}
+
+func TestTruncateCaption(t *testing.T) {
+	if truncateCaption("short") != "short" {
+		t.Error("Short captions should be kept")
+	}
+
+	caption := "*Logs for 3 pods*\n" + strings.Repeat("⚠️ api-1: `error`\n", 100)
+	result := truncateCaption(caption)
+	if utf8.RuneCountInString(result) > maxCaptionLength {
+		t.Errorf("truncateCaption() has %d runes, expected at most %d", utf8.RuneCountInString(result), maxCaptionLength)
+	}
+	if !strings.HasSuffix(result, "`\n...") || strings.Count(result, "`")%2 != 0 {
+		t.Errorf("truncateCaption() should cut at a line break, got %q", result[len(result)-40:])
+	}
+}
+
+func TestCodeSpan(t *testing.T) {
+	result := codeSpan("pods \"api_1\" is forbidden: `*`")
+	expected := "`pods \"api_1\" is forbidden: '*'`"
+	if result != expected {
+		t.Errorf("codeSpan() = %q, expected %q", result, expected)
+	}
+}
+
+func TestTailPreview_RuneBoundary(t *testing.T) {
+	text := strings.Repeat("日本語", 500)
+	result := tailPreview(text, 10, 100)
+
+	if !utf8.ValidString(result) {
+		t.Error("Preview should not split multi-byte runes")
+	}
+	if utf8.RuneCountInString(result) != 100 {
+		t.Errorf("Preview length = %d runes, expected 100", utf8.RuneCountInString(result))
+	}
+}
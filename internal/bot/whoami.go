@@ -0,0 +1,205 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"kubectl-bot/internal/rbac"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// handleWhoAmI handles the /whoami command
+func (b *Bot) handleWhoAmI(ctx context.Context, message *tgbotapi.Message) {
+	b.sendMessage(message.Chat.ID, b.describeUser(ctx, message.From.ID))
+}
+
+// describeUser shows the role, teams, accessible namespaces and granted verbs of a user
+func (b *Bot) describeUser(ctx context.Context, userID int64) string {
+	text := fmt.Sprintf("Your role: *%s*\nUser ID: `%d`\n", b.getUserRole(ctx, userID), userID)
+
+	if teams, err := b.rbac.GetUserTeams(ctx, userID); err == nil && len(teams) > 0 {
+		names := make([]string, 0, len(teams))
+		for _, team := range teams {
+			names = append(names, team.Name)
+		}
+		text += fmt.Sprintf("Teams: %s\n", strings.Join(names, ", "))
+	}
+
+	namespaces, err := b.validator.ValidateAndGetNamespaces(ctx, userID)
+	switch {
+	case err != nil && !apierrors.IsNotFound(err):
+		text += fmt.Sprintf("\n*Namespaces:* unavailable (%v)\n", err)
+	case len(namespaces) == 0:
+		// Users without any permissions have no namespaces either
+		text += "\n*Namespaces:* none\n"
+	default:
+		text += fmt.Sprintf("\n*Namespaces:* %s\n", strings.Join(namespaces, ", "))
+	}
+
+	if b.rbac.IsBootstrapAdmin(userID) {
+		return text + "\n*Granted:*\n• every verb on every resource (bootstrap admin)\n"
+	}
+
+	access, err := b.rbac.GetAccess(ctx, userID)
+	if err != nil {
+		return text + "\n*Granted:* nothing yet. Ask an admin for access.\n"
+	}
+
+	return text + formatAccess(access, time.Now())
+}
+
+// formatAccess lists the verbs and unexpired permission entries of a user
+// together with where each entry comes from, followed by the deny rules
+func formatAccess(access *rbac.Access, now time.Time) string {
+	if access.Role == rbac.RoleAdmin {
+		text := "\n*Granted:*\n• every verb on every resource (admin role)\n"
+		return text + formatDenyRules(access.Deny)
+	}
+
+	verbs := grantedVerbs(access.Permissions, now)
+	if len(verbs) == 0 {
+		return "\n*Granted:* nothing yet. Ask an admin for access.\n" + formatDenyRules(access.Deny)
+	}
+
+	text := fmt.Sprintf("\n*Verbs:* %s\n\n*Granted:*\n", strings.Join(verbs, ", "))
+	for i, p := range access.Permissions {
+		if p.Expired(now) {
+			continue
+		}
+		text += fmt.Sprintf("• `%s` (%s", rbac.DescribeRule(p), access.Source(i))
+		if p.ExpiresAt != nil {
+			text += fmt.Sprintf(", until %s", p.ExpiresAt.UTC().Format(time.RFC3339))
+		}
+		text += ")\n"
+	}
+
+	return text + formatDenyRules(access.Deny)
+}
+
+// formatDenyRules lists deny rules, or nothing if there are none
+func formatDenyRules(deny []rbac.Permission) string {
+	if len(deny) == 0 {
+		return ""
+	}
+
+	text := "\n*Denied:*\n"
+	for _, d := range deny {
+		text += fmt.Sprintf("• `%s`\n", rbac.DescribeRule(d))
+	}
+	return text
+}
+
+// grantedVerbs returns the distinct verbs of the unexpired permission entries, sorted
+func grantedVerbs(permissions []rbac.Permission, now time.Time) []string {
+	seen := map[string]bool{}
+	verbs := []string{}
+	for _, p := range permissions {
+		if p.Expired(now) {
+			continue
+		}
+		for _, verb := range p.Verbs {
+			if !seen[verb] {
+				seen[verb] = true
+				verbs = append(verbs, verb)
+			}
+		}
+	}
+	sort.Strings(verbs)
+	return verbs
+}
+
+// canIArgs returns the arguments of a /can_i command. Telegram ends a command at
+// the first character other than a letter, digit or underscore, so /can-i arrives
+// as command "can" with arguments starting with "i", which are stripped here.
+func canIArgs(message *tgbotapi.Message) (string, bool) {
+	args := message.CommandArguments()
+	switch message.Command() {
+	case "can_i":
+		return args, true
+	case "can":
+		if args == "i" {
+			return "", true
+		}
+		if strings.HasPrefix(args, "i ") {
+			return strings.TrimPrefix(args, "i "), true
+		}
+	}
+	return "", false
+}
+
+// handleCanI handles the /can_i command: it runs a permission check for the user
+// and explains what allowed or denied it
+func (b *Bot) handleCanI(ctx context.Context, message *tgbotapi.Message, arguments string) {
+	userID := message.From.ID
+	args := strings.Fields(arguments)
+
+	namespace := "default"
+	positional := []string{}
+
+	// Parse the verb, resource, optional name and flags
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-n" && i+1 < len(args) {
+			namespace = args[i+1]
+			i++
+		} else {
+			positional = append(positional, args[i])
+		}
+	}
+
+	if len(positional) < 2 || len(positional) > 3 {
+		b.sendMessage(message.Chat.ID, "Usage: /can\\_i <verb> <resource> [name] [-n <namespace>]")
+		return
+	}
+
+	check := rbac.PermissionCheck{
+		TelegramUserID: userID,
+		Namespace:      rbac.NormalizeNamespace(namespace),
+		Verb:           positional[0],
+		Resource:       positional[1],
+	}
+	if len(positional) == 3 {
+		check.ResourceName = positional[2]
+	}
+
+	decision, err := b.validator.Explain(ctx, check)
+	if err != nil && decision.Reason == "" {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Error: %v", err))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, formatDecision(check, decision))
+}
+
+// formatDecision explains the outcome of a permission check. The verb, resource,
+// name and namespace come from the user, so they are put in code spans.
+func formatDecision(check rbac.PermissionCheck, decision rbac.Decision) string {
+	target := codeSpan(check.Resource)
+	if check.ResourceName != "" {
+		target += " " + codeSpan(check.ResourceName)
+	}
+	question := fmt.Sprintf("%s %s in namespace %s", codeSpan(check.Verb), target, codeSpan(check.Namespace))
+
+	if !decision.Allowed {
+		text := fmt.Sprintf("❌ No, you cannot %s\n\n", question)
+		if decision.Rule != nil {
+			return text + fmt.Sprintf("Denied by %s: %s", decision.Source, codeSpan(rbac.DescribeRule(*decision.Rule)))
+		}
+		return text + fmt.Sprintf("Reason: %s", codeSpan(decision.Reason))
+	}
+
+	text := fmt.Sprintf("✅ Yes, you can %s\n\nAllowed by: %s", question, decision.Source)
+	if decision.Rule == nil {
+		return text
+	}
+
+	text += fmt.Sprintf("\nRule: %s", codeSpan(rbac.DescribeRule(*decision.Rule)))
+	if check.ResourceName == "" && (len(decision.Rule.ResourceNames) > 0 || decision.Rule.Selector != "") {
+		text += "\n\nList results only include objects matching the names and selectors of your entries."
+	}
+	return text
+}
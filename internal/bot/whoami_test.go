@@ -0,0 +1,144 @@
+package bot
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"kubectl-bot/internal/rbac"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGrantedVerbs(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := metav1.NewTime(now.Add(-time.Hour))
+
+	verbs := grantedVerbs([]rbac.Permission{
+		{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"logs", "get"}},
+		{Namespace: "production", Resources: []string{"deployments"}, Verbs: []string{"restart", "get"}},
+		{Namespace: "production", Resources: []string{"deployments"}, Verbs: []string{"scale"}, ExpiresAt: &past},
+	}, now)
+
+	expected := []string{"get", "logs", "restart"}
+	if !reflect.DeepEqual(verbs, expected) {
+		t.Errorf("grantedVerbs() = %v, expected %v", verbs, expected)
+	}
+}
+
+func TestFormatAccess(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	access := &rbac.Access{
+		Role: rbac.RoleViewer,
+		Permissions: []rbac.Permission{
+			{Namespace: "production", Resources: []string{"deployments"}, Verbs: []string{"restart"}, ResourceNames: []string{"api-*"}},
+		},
+		Sources: []string{"team 'backend'"},
+		Deny:    []rbac.Permission{{Namespace: "production", Resources: []string{"pods"}, Verbs: []string{"logs"}}},
+	}
+
+	text := formatAccess(access, now)
+	for _, expected := range []string{
+		"*Verbs:* restart",
+		"`restart on deployments in namespace 'production' named api-*` (team 'backend')",
+		"*Denied:*\n• `logs on pods in namespace 'production'`",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("formatAccess() = %q, expected it to contain %q", text, expected)
+		}
+	}
+
+	if text := formatAccess(&rbac.Access{Role: rbac.RoleAdmin}, now); !strings.Contains(text, "every verb on every resource (admin role)") {
+		t.Errorf("formatAccess() = %q, expected admins to have every verb", text)
+	}
+}
+
+func TestCanIArgs(t *testing.T) {
+	command := func(text string, length int) *tgbotapi.Message {
+		return &tgbotapi.Message{
+			Text:     text,
+			Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: length}},
+		}
+	}
+
+	tests := []struct {
+		message  *tgbotapi.Message
+		expected string
+		ok       bool
+	}{
+		{command("/can_i restart deployments api", 6), "restart deployments api", true},
+		{command("/can_i@kbot logs pods -n staging", 11), "logs pods -n staging", true},
+		// Telegram ends the command at the dash
+		{command("/can-i restart deployments", 4), "restart deployments", true},
+		{command("/can-i", 4), "", true},
+		{command("/can restart deployments", 4), "", false},
+		{command("/can-it restart deployments", 4), "", false},
+	}
+
+	for _, tt := range tests {
+		args, ok := canIArgs(tt.message)
+		if args != tt.expected || ok != tt.ok {
+			t.Errorf("canIArgs(%q) = %q, %v, expected %q, %v", tt.message.Text, args, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestFormatDecision(t *testing.T) {
+	rule := rbac.Permission{Namespace: "staging", Resources: []string{"deployments"}, Verbs: []string{"restart"}}
+	named := rbac.PermissionCheck{Namespace: "staging", Resource: "deployments", Verb: "restart", ResourceName: "api"}
+
+	tests := []struct {
+		description string
+		check       rbac.PermissionCheck
+		decision    rbac.Decision
+		expected    []string
+	}{
+		{
+			"Allowed by a permission entry",
+			named,
+			rbac.Decision{Allowed: true, Source: "own permission entry", Rule: &rule},
+			[]string{"✅ Yes, you can `restart` `deployments` `api` in namespace `staging`", "Allowed by: own permission entry", "Rule: `restart on deployments in namespace 'staging'`"},
+		},
+		{
+			"Allowed by the bootstrap admin list",
+			named,
+			rbac.Decision{Allowed: true, Source: "bootstrap admin"},
+			[]string{"Allowed by: bootstrap admin"},
+		},
+		{
+			"Denied by a deny rule",
+			named,
+			rbac.Decision{Reason: "Permission denied by deny rule: ...", Source: "deny rule", Rule: &rule},
+			[]string{"❌ No, you cannot", "Denied by deny rule: `restart on deployments in namespace 'staging'`"},
+		},
+		{
+			"Denied without a matching entry",
+			named,
+			rbac.Decision{Reason: "Permission denied: missing 'restart' access to deployments in namespace 'staging'"},
+			[]string{"Reason: `Permission denied: missing 'restart' access to deployments in namespace 'staging'`"},
+		},
+		{
+			"Wildcard resource in a namespace with an underscore",
+			rbac.PermissionCheck{Namespace: "my_ns", Resource: "*", Verb: "get"},
+			rbac.Decision{Reason: "Permission denied: missing 'get' access to * in namespace 'my_ns'"},
+			[]string{"❌ No, you cannot `get` `*` in namespace `my_ns`"},
+		},
+		{
+			"List limited by names",
+			rbac.PermissionCheck{Namespace: "staging", Resource: "deployments", Verb: "list"},
+			rbac.Decision{Allowed: true, Source: "viewer role default", Rule: &rbac.Permission{Namespace: "staging", Resources: []string{"*"}, Verbs: []string{"list"}, ResourceNames: []string{"api-*"}}},
+			[]string{"Allowed by: viewer role default", "List results only include objects matching"},
+		},
+	}
+
+	for _, tt := range tests {
+		text := formatDecision(tt.check, tt.decision)
+		for _, expected := range tt.expected {
+			if !strings.Contains(text, expected) {
+				t.Errorf("%s: formatDecision() = %q, expected it to contain %q", tt.description, text, expected)
+			}
+		}
+	}
+}
@@ -3,16 +3,29 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// defaultApprovalTTL is how long approval requests stay open unless APPROVAL_TTL is set
+const defaultApprovalTTL = 30 * time.Minute
+
 type Config struct {
 	TelegramBotToken  string
 	AdminTelegramIDs  []int64
 	LogLevel          string
 	BotNamespace      string
 	BotDeploymentName string
+	RedactionPatterns []string
+
+	// Mutating commands in protected namespaces need approval of a second user
+	ProtectedNamespaces []string
+	ApprovalTTL         time.Duration
+
+	// Elevations into these namespaces need approval of an admin
+	ElevationApprovalNamespaces []string
 }
 
 // Load loads configuration from environment variables
@@ -49,12 +62,37 @@ func Load() (*Config, error) {
 		botDeploymentName = "telegram-bot"
 	}
 
+	// Optional: extra regexes for secrets to redact from output (one per line)
+	redactionPatterns, err := parseRedactionPatterns(os.Getenv("REDACTION_PATTERNS"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse REDACTION_PATTERNS: %w", err)
+	}
+
+	// Optional: namespaces where changes need a second user's approval (comma-separated)
+	protectedNamespaces := parseNamespaces(os.Getenv("PROTECTED_NAMESPACES"))
+
+	// Optional: namespaces where /elevate needs an admin's approval (comma-separated)
+	elevationApprovalNamespaces := parseNamespaces(os.Getenv("ELEVATION_APPROVAL_NAMESPACES"))
+
+	approvalTTL := defaultApprovalTTL
+	if value := os.Getenv("APPROVAL_TTL"); value != "" {
+		approvalTTL, err = time.ParseDuration(value)
+		if err != nil || approvalTTL <= 0 {
+			return nil, fmt.Errorf("invalid APPROVAL_TTL '%s': must be a positive duration such as 30m", value)
+		}
+	}
+
 	return &Config{
-		TelegramBotToken:  token,
-		AdminTelegramIDs:  adminIDs,
-		LogLevel:          logLevel,
-		BotNamespace:      botNamespace,
-		BotDeploymentName: botDeploymentName,
+		TelegramBotToken:    token,
+		AdminTelegramIDs:    adminIDs,
+		LogLevel:            logLevel,
+		BotNamespace:        botNamespace,
+		BotDeploymentName:   botDeploymentName,
+		RedactionPatterns:   redactionPatterns,
+		ProtectedNamespaces: protectedNamespaces,
+		ApprovalTTL:         approvalTTL,
+
+		ElevationApprovalNamespaces: elevationApprovalNamespaces,
 	}, nil
 }
 
@@ -82,6 +120,57 @@ func parseAdminIDs(s string) ([]int64, error) {
 	return ids, nil
 }
 
+func parseRedactionPatterns(s string) ([]string, error) {
+	patterns := []string{}
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if _, err := regexp.Compile(line); err != nil {
+			return nil, fmt.Errorf("invalid pattern '%s': %w", line, err)
+		}
+		patterns = append(patterns, line)
+	}
+
+	return patterns, nil
+}
+
+func parseNamespaces(s string) []string {
+	namespaces := []string{}
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			namespaces = append(namespaces, part)
+		}
+	}
+
+	return namespaces
+}
+
+// IsProtectedNamespace checks if changes in a namespace need a second user's approval
+func (c *Config) IsProtectedNamespace(namespace string) bool {
+	for _, protected := range c.ProtectedNamespaces {
+		if protected == namespace || protected == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// ElevationNeedsApproval checks if elevating into a namespace needs an admin's approval
+func (c *Config) ElevationNeedsApproval(namespace string) bool {
+	for _, ns := range c.ElevationApprovalNamespaces {
+		if ns == namespace || ns == "*" {
+			return true
+		}
+	}
+	return false
+}
+
 // IsBootstrapAdmin checks if a user ID is in the bootstrap admin list
 func (c *Config) IsBootstrapAdmin(userID int64) bool {
 	for _, adminID := range c.AdminTelegramIDs {
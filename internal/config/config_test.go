@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestLoad_Success(t *testing.T) {
@@ -222,3 +223,106 @@ func TestParseAdminIDs_Errors(t *testing.T) {
 		}
 	}
 }
+
+func TestParseRedactionPatterns(t *testing.T) {
+	patterns, err := parseRedactionPatterns("internal-token-[a-z0-9]+\n\n  (?i)x-api-key: \\S+  \n")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(patterns) != 2 {
+		t.Fatalf("Expected 2 patterns, got %d: %v", len(patterns), patterns)
+	}
+
+	if patterns[1] != `(?i)x-api-key: \S+` {
+		t.Errorf("Expected trimmed pattern, got %q", patterns[1])
+	}
+
+	if _, err := parseRedactionPatterns("valid\n(unclosed"); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
+
+func TestLoad_InvalidRedactionPattern(t *testing.T) {
+	os.Setenv("TELEGRAM_BOT_TOKEN", "test-token")
+	os.Setenv("ADMIN_TELEGRAM_IDS", "123456789")
+	os.Setenv("REDACTION_PATTERNS", "[invalid")
+	defer os.Unsetenv("TELEGRAM_BOT_TOKEN")
+	defer os.Unsetenv("ADMIN_TELEGRAM_IDS")
+	defer os.Unsetenv("REDACTION_PATTERNS")
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("Expected error for invalid REDACTION_PATTERNS, got nil")
+	}
+}
+
+func TestLoad_ApprovalConfig(t *testing.T) {
+	os.Setenv("TELEGRAM_BOT_TOKEN", "test-token")
+	os.Setenv("ADMIN_TELEGRAM_IDS", "123456789")
+	os.Setenv("PROTECTED_NAMESPACES", "production, payments,")
+	os.Setenv("APPROVAL_TTL", "1h")
+	defer os.Unsetenv("TELEGRAM_BOT_TOKEN")
+	defer os.Unsetenv("ADMIN_TELEGRAM_IDS")
+	defer os.Unsetenv("PROTECTED_NAMESPACES")
+	defer os.Unsetenv("APPROVAL_TTL")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(cfg.ProtectedNamespaces) != 2 || cfg.ProtectedNamespaces[1] != "payments" {
+		t.Errorf("Expected protected namespaces [production payments], got %v", cfg.ProtectedNamespaces)
+	}
+
+	if cfg.ApprovalTTL != time.Hour {
+		t.Errorf("Expected approval TTL 1h, got %v", cfg.ApprovalTTL)
+	}
+
+	os.Setenv("APPROVAL_TTL", "soon")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for invalid APPROVAL_TTL, got nil")
+	}
+
+	os.Unsetenv("APPROVAL_TTL")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.ApprovalTTL != defaultApprovalTTL {
+		t.Errorf("Expected default approval TTL %v, got %v", defaultApprovalTTL, cfg.ApprovalTTL)
+	}
+}
+
+func TestIsProtectedNamespace(t *testing.T) {
+	cfg := &Config{ProtectedNamespaces: []string{"production"}}
+
+	if !cfg.IsProtectedNamespace("production") {
+		t.Error("Expected production to be protected")
+	}
+	if cfg.IsProtectedNamespace("staging") {
+		t.Error("Expected staging not to be protected")
+	}
+
+	cfg = &Config{ProtectedNamespaces: []string{"*"}}
+	if !cfg.IsProtectedNamespace("staging") {
+		t.Error("Expected * to protect every namespace")
+	}
+}
+
+func TestElevationNeedsApproval(t *testing.T) {
+	cfg := &Config{ElevationApprovalNamespaces: []string{"production"}}
+
+	if !cfg.ElevationNeedsApproval("production") {
+		t.Error("Expected elevation into production to need approval")
+	}
+	if cfg.ElevationNeedsApproval("staging") {
+		t.Error("Expected elevation into staging not to need approval")
+	}
+
+	cfg = &Config{ElevationApprovalNamespaces: []string{"*"}}
+	if !cfg.ElevationNeedsApproval("staging") {
+		t.Error("Expected * to require approval everywhere")
+	}
+}
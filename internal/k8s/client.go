@@ -70,6 +70,11 @@ func (c *Client) ListNamespaces(ctx context.Context) (*corev1.NamespaceList, err
 	return c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 }
 
+// GetNamespace returns a namespace by name
+func (c *Client) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	return c.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+}
+
 // TelegramBotPermissionGVR returns the GroupVersionResource for TelegramBotPermission
 func TelegramBotPermissionGVR() schema.GroupVersionResource {
 	return schema.GroupVersionResource{
@@ -79,6 +84,24 @@ func TelegramBotPermissionGVR() schema.GroupVersionResource {
 	}
 }
 
+// TelegramBotApprovalGVR returns the GroupVersionResource for TelegramBotApproval
+func TelegramBotApprovalGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "kbot.go.mamad.dev",
+		Version:  "v1",
+		Resource: "telegrambotapprovals",
+	}
+}
+
+// TelegramBotTeamGVR returns the GroupVersionResource for TelegramBotTeam
+func TelegramBotTeamGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "kbot.go.mamad.dev",
+		Version:  "v1",
+		Resource: "telegrambotteams",
+	}
+}
+
 // AddToScheme adds known types to scheme
 func AddToScheme(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(
@@ -1,7 +1,9 @@
 package k8s
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -45,3 +47,38 @@ func TestTelegramBotPermissionGVR_Consistency(t *testing.T) {
 		t.Error("TelegramBotPermissionGVR should return consistent values")
 	}
 }
+
+func TestTelegramBotApprovalGVR(t *testing.T) {
+	gvr := TelegramBotApprovalGVR()
+
+	if gvr.GroupVersion() != TelegramBotPermissionGVR().GroupVersion() {
+		t.Errorf("Expected approvals in the permission API group, got '%s'", gvr.GroupVersion())
+	}
+
+	if gvr.Resource != "telegrambotapprovals" {
+		t.Errorf("Expected resource 'telegrambotapprovals', got '%s'", gvr.Resource)
+	}
+}
+
+func TestManualJobName(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		cronJobName string
+		expected    string
+	}{
+		{"nightly-backup", "nightly-backup-manual-1700000000"},
+		{strings.Repeat("a", 60), strings.Repeat("a", 34) + "-manual-1700000000"},
+		{strings.Repeat("a", 33) + "-backup", strings.Repeat("a", 33) + "-manual-1700000000"},
+	}
+
+	for _, tt := range tests {
+		result := ManualJobName(tt.cronJobName, now)
+		if result != tt.expected {
+			t.Errorf("ManualJobName(%q) = %q, expected %q", tt.cronJobName, result, tt.expected)
+		}
+		if len(result) > 52 {
+			t.Errorf("ManualJobName(%q) length = %d, expected <= 52", tt.cronJobName, len(result))
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ListCronJobs lists cronjobs in a namespace with optional label selector
+func (c *Client) ListCronJobs(ctx context.Context, namespace string, selector string) (*batchv1.CronJobList, error) {
+	opts := metav1.ListOptions{}
+
+	if selector != "" {
+		// Validate selector
+		if _, err := labels.Parse(selector); err != nil {
+			return nil, fmt.Errorf("invalid label selector '%s': %w", selector, err)
+		}
+		opts.LabelSelector = selector
+	}
+
+	if namespace == "" {
+		namespace = corev1.NamespaceAll
+	}
+
+	return c.clientset.BatchV1().CronJobs(namespace).List(ctx, opts)
+}
+
+// GetCronJob gets a specific cronjob
+func (c *Client) GetCronJob(ctx context.Context, namespace, name string) (*batchv1.CronJob, error) {
+	if namespace == "" {
+		namespace = corev1.NamespaceDefault
+	}
+
+	return c.clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// TriggerCronJob creates a job from the cronjob's job template,
+// like `kubectl create job --from=cronjob/<name>`
+func (c *Client) TriggerCronJob(ctx context.Context, namespace, name string) (*batchv1.Job, error) {
+	if namespace == "" {
+		namespace = corev1.NamespaceDefault
+	}
+
+	cronJob, err := c.GetCronJob(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := map[string]string{"cronjob.kubernetes.io/instantiate": "manual"}
+	for k, v := range cronJob.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+
+	jobLabels := make(map[string]string)
+	for k, v := range cronJob.Spec.JobTemplate.Labels {
+		jobLabels[k] = v
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ManualJobName(name, time.Now()),
+			Namespace:   namespace,
+			Labels:      jobLabels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind("CronJob")),
+			},
+		},
+		Spec: *cronJob.Spec.JobTemplate.Spec.DeepCopy(),
+	}
+
+	return c.clientset.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+}
+
+// SuspendCronJob suspends or resumes a cronjob
+func (c *Client) SuspendCronJob(ctx context.Context, namespace, name string, suspend bool) error {
+	if namespace == "" {
+		namespace = corev1.NamespaceDefault
+	}
+
+	cronJob, err := c.GetCronJob(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	cronJob.Spec.Suspend = &suspend
+	_, err = c.clientset.BatchV1().CronJobs(namespace).Update(ctx, cronJob, metav1.UpdateOptions{})
+	return err
+}
+
+// CronJobMatchesSelector checks if a cronjob matches the given label selector
+func (c *Client) CronJobMatchesSelector(ctx context.Context, namespace, cronJobName, selector string) (bool, error) {
+	if selector == "" {
+		return true, nil
+	}
+
+	cronJob, err := c.GetCronJob(ctx, namespace, cronJobName)
+	if err != nil {
+		return false, err
+	}
+
+	labelSelector, err := labels.Parse(selector)
+	if err != nil {
+		return false, fmt.Errorf("invalid selector: %w", err)
+	}
+
+	return labelSelector.Matches(labels.Set(cronJob.Labels)), nil
+}
+
+// maxJobNameLength is the longest job name kubectl creates. The job controller adds
+// a pod name suffix and puts the job name in a pod label, which allows 63 characters.
+const maxJobNameLength = 52
+
+// ManualJobName builds the name of a manually triggered job.
+// The cronjob name is shortened so the result fits maxJobNameLength.
+func ManualJobName(cronJobName string, now time.Time) string {
+	suffix := fmt.Sprintf("-manual-%d", now.Unix())
+	if maxLen := maxJobNameLength - len(suffix); len(cronJobName) > maxLen {
+		// A cut right after a dash would leave a double dash before the suffix
+		cronJobName = strings.TrimRight(cronJobName[:maxLen], "-")
+	}
+	return cronJobName + suffix
+}
@@ -0,0 +1,78 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ListDaemonSets lists daemonsets in a namespace with optional label selector
+func (c *Client) ListDaemonSets(ctx context.Context, namespace string, selector string) (*appsv1.DaemonSetList, error) {
+	opts := metav1.ListOptions{}
+
+	if selector != "" {
+		// Validate selector
+		if _, err := labels.Parse(selector); err != nil {
+			return nil, fmt.Errorf("invalid label selector '%s': %w", selector, err)
+		}
+		opts.LabelSelector = selector
+	}
+
+	if namespace == "" {
+		namespace = corev1.NamespaceAll
+	}
+
+	return c.clientset.AppsV1().DaemonSets(namespace).List(ctx, opts)
+}
+
+// GetDaemonSet gets a specific daemonset
+func (c *Client) GetDaemonSet(ctx context.Context, namespace, name string) (*appsv1.DaemonSet, error) {
+	if namespace == "" {
+		namespace = corev1.NamespaceDefault
+	}
+
+	return c.clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// RestartDaemonSet restarts a daemonset by updating its annotation
+func (c *Client) RestartDaemonSet(ctx context.Context, namespace, name string) error {
+	if namespace == "" {
+		namespace = corev1.NamespaceDefault
+	}
+
+	daemonSet, err := c.GetDaemonSet(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	if daemonSet.Spec.Template.Annotations == nil {
+		daemonSet.Spec.Template.Annotations = make(map[string]string)
+	}
+	daemonSet.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = metav1.Now().Format("2006-01-02T15:04:05Z07:00")
+
+	_, err = c.clientset.AppsV1().DaemonSets(namespace).Update(ctx, daemonSet, metav1.UpdateOptions{})
+	return err
+}
+
+// DaemonSetMatchesSelector checks if a daemonset matches the given label selector
+func (c *Client) DaemonSetMatchesSelector(ctx context.Context, namespace, daemonSetName, selector string) (bool, error) {
+	if selector == "" {
+		return true, nil
+	}
+
+	daemonSet, err := c.GetDaemonSet(ctx, namespace, daemonSetName)
+	if err != nil {
+		return false, err
+	}
+
+	labelSelector, err := labels.Parse(selector)
+	if err != nil {
+		return false, fmt.Errorf("invalid selector: %w", err)
+	}
+
+	return labelSelector.Matches(labels.Set(daemonSet.Labels)), nil
+}
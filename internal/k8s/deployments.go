@@ -3,6 +3,8 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -58,49 +60,150 @@ func (c *Client) RestartDeployment(ctx context.Context, namespace, name string)
 	return err
 }
 
-// RollbackDeployment rolls back a deployment to the previous revision
-func (c *Client) RollbackDeployment(ctx context.Context, namespace, name string) error {
+// revisionAnnotation holds the rollout revision of a deployment and its replica sets
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// DeploymentRevision is one rollout revision of a deployment
+type DeploymentRevision struct {
+	Number     int64
+	ReplicaSet string
+	Images     []string
+	template   corev1.PodTemplateSpec
+}
+
+// GetRollbackTarget returns the current revision of a deployment and the
+// previous revision that RollbackDeployment would restore
+func (c *Client) GetRollbackTarget(ctx context.Context, namespace, name string) (*DeploymentRevision, *DeploymentRevision, error) {
+	deployment, revisions, err := c.listDeploymentRevisions(ctx, namespace, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	current, previous := rollbackRevisions(deployment, revisions)
+	if previous == nil {
+		return nil, nil, fmt.Errorf("no previous revision found")
+	}
+
+	return current, previous, nil
+}
+
+// RollbackDeployment rolls a deployment back to the given revision, or to the
+// previous revision when revision is 0
+func (c *Client) RollbackDeployment(ctx context.Context, namespace, name string, revision int64) error {
 	if namespace == "" {
 		namespace = corev1.NamespaceDefault
 	}
 
-	// Get current deployment
-	deployment, err := c.GetDeployment(ctx, namespace, name)
+	deployment, revisions, err := c.listDeploymentRevisions(ctx, namespace, name)
 	if err != nil {
 		return err
 	}
 
-	// Get replica sets
+	var target *DeploymentRevision
+	if revision == 0 {
+		_, target = rollbackRevisions(deployment, revisions)
+		if target == nil {
+			return fmt.Errorf("no previous revision found")
+		}
+	} else {
+		for i := range revisions {
+			if revisions[i].Number == revision {
+				target = &revisions[i]
+				break
+			}
+		}
+		if target == nil {
+			return fmt.Errorf("revision %d not found", revision)
+		}
+	}
+
+	// Restore the revision's pod template without the label the controller adds to each replica set
+	template := target.template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+
+	deployment.Spec.Template = *template
+	_, err = c.clientset.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
+	return err
+}
+
+// listDeploymentRevisions returns a deployment with the revisions of the replica sets it owns
+func (c *Client) listDeploymentRevisions(ctx context.Context, namespace, name string) (*appsv1.Deployment, []DeploymentRevision, error) {
+	if namespace == "" {
+		namespace = corev1.NamespaceDefault
+	}
+
+	deployment, err := c.GetDeployment(ctx, namespace, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid deployment selector: %w", err)
+	}
+
 	rsList, err := c.clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: labels.Set(deployment.Spec.Selector.MatchLabels).String(),
+		LabelSelector: selector.String(),
 	})
 	if err != nil {
-		return fmt.Errorf("failed to list replica sets: %w", err)
+		return nil, nil, fmt.Errorf("failed to list replica sets: %w", err)
 	}
 
-	if len(rsList.Items) < 2 {
-		return fmt.Errorf("no previous revision found")
-	}
+	return deployment, replicaSetRevisions(deployment, rsList.Items), nil
+}
 
-	// Find previous revision (second most recent)
-	var previousRS *appsv1.ReplicaSet
-	for i := range rsList.Items {
-		rs := &rsList.Items[i]
-		if rs.Name != deployment.Name && rs.Annotations["deployment.kubernetes.io/revision"] != "" {
-			if previousRS == nil || rs.CreationTimestamp.After(previousRS.CreationTimestamp.Time) {
-				previousRS = rs
-			}
+// replicaSetRevisions returns the revisions of the replica sets owned by a deployment, newest first
+func replicaSetRevisions(deployment *appsv1.Deployment, replicaSets []appsv1.ReplicaSet) []DeploymentRevision {
+	revisions := []DeploymentRevision{}
+	for i := range replicaSets {
+		rs := &replicaSets[i]
+		if !metav1.IsControlledBy(rs, deployment) {
+			continue
 		}
+
+		number, err := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		images := []string{}
+		for _, container := range rs.Spec.Template.Spec.Containers {
+			images = append(images, container.Image)
+		}
+
+		revisions = append(revisions, DeploymentRevision{
+			Number:     number,
+			ReplicaSet: rs.Name,
+			Images:     images,
+			template:   rs.Spec.Template,
+		})
 	}
 
-	if previousRS == nil {
-		return fmt.Errorf("no previous revision found")
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Number > revisions[j].Number
+	})
+	return revisions
+}
+
+// rollbackRevisions picks the current revision of a deployment and the newest
+// revision before it. Either may be nil.
+func rollbackRevisions(deployment *appsv1.Deployment, revisions []DeploymentRevision) (*DeploymentRevision, *DeploymentRevision) {
+	currentNumber, err := strconv.ParseInt(deployment.Annotations[revisionAnnotation], 10, 64)
+	if err != nil && len(revisions) > 0 {
+		currentNumber = revisions[0].Number
+	}
+
+	var current, previous *DeploymentRevision
+	for i := range revisions {
+		revision := &revisions[i]
+		if revision.Number == currentNumber {
+			current = revision
+		} else if revision.Number < currentNumber && previous == nil {
+			previous = revision
+		}
 	}
 
-	// Update deployment to use previous template
-	deployment.Spec.Template = previousRS.Spec.Template
-	_, err = c.clientset.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
-	return err
+	return current, previous
 }
 
 // ScaleDeployment scales a deployment to the specified number of replicas
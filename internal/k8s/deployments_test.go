@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func testReplicaSet(name, revision string, owner *appsv1.Deployment, image string) appsv1.ReplicaSet {
+	controller := true
+	rs := appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{revisionAnnotation: revision},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}},
+			},
+		},
+	}
+	if owner != nil {
+		rs.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       owner.Name,
+			UID:        owner.UID,
+			Controller: &controller,
+		}}
+	}
+	return rs
+}
+
+func TestRollbackRevisions(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "api",
+			UID:         types.UID("api-uid"),
+			Annotations: map[string]string{revisionAnnotation: "3"},
+		},
+	}
+	other := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api-canary", UID: types.UID("canary-uid")}}
+
+	replicaSets := []appsv1.ReplicaSet{
+		testReplicaSet("api-1", "1", deployment, "api:v1"),
+		testReplicaSet("api-3", "3", deployment, "api:v3"),
+		testReplicaSet("api-2", "2", deployment, "api:v2"),
+		testReplicaSet("api-canary-9", "9", other, "api:canary"),
+		testReplicaSet("orphan", "8", nil, "api:orphan"),
+		testReplicaSet("api-bad", "not-a-number", deployment, "api:bad"),
+	}
+
+	revisions := replicaSetRevisions(deployment, replicaSets)
+	if len(revisions) != 3 {
+		t.Fatalf("replicaSetRevisions() returned %d revisions, expected 3 owned ones: %+v", len(revisions), revisions)
+	}
+	if revisions[0].Number != 3 || revisions[2].Number != 1 {
+		t.Errorf("replicaSetRevisions() not sorted newest first: %+v", revisions)
+	}
+
+	current, previous := rollbackRevisions(deployment, revisions)
+	if current == nil || current.ReplicaSet != "api-3" {
+		t.Errorf("current = %+v, expected api-3", current)
+	}
+	if previous == nil || previous.ReplicaSet != "api-2" || previous.Images[0] != "api:v2" {
+		t.Errorf("previous = %+v, expected api-2", previous)
+	}
+
+	// After rolling back to revision 2, the controller gives it revision 4
+	deployment.Annotations[revisionAnnotation] = "4"
+	revisions = replicaSetRevisions(deployment, []appsv1.ReplicaSet{
+		testReplicaSet("api-1", "1", deployment, "api:v1"),
+		testReplicaSet("api-3", "3", deployment, "api:v3"),
+		testReplicaSet("api-2", "4", deployment, "api:v2"),
+	})
+	_, previous = rollbackRevisions(deployment, revisions)
+	if previous == nil || previous.Number != 3 {
+		t.Errorf("previous = %+v, expected revision 3", previous)
+	}
+
+	// A deployment without older revisions has nothing to roll back to
+	_, previous = rollbackRevisions(deployment, revisions[:1])
+	if previous != nil {
+		t.Errorf("previous = %+v, expected nil", previous)
+	}
+}
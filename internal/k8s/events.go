@@ -0,0 +1,63 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// EventFilter narrows down the events returned by ListEvents
+type EventFilter struct {
+	Kind string // Involved object kind (e.g., "Pod")
+	Name string // Involved object name
+	Type string // Event type: "Warning" or "Normal"
+}
+
+// ListEvents lists events in a namespace, oldest first, filtered by involved object and type
+func (c *Client) ListEvents(ctx context.Context, namespace string, filter EventFilter) (*corev1.EventList, error) {
+	fieldSet := fields.Set{}
+	if filter.Kind != "" {
+		fieldSet["involvedObject.kind"] = filter.Kind
+	}
+	if filter.Name != "" {
+		fieldSet["involvedObject.name"] = filter.Name
+	}
+	if filter.Type != "" {
+		fieldSet["type"] = filter.Type
+	}
+
+	opts := metav1.ListOptions{}
+	if len(fieldSet) > 0 {
+		opts.FieldSelector = fields.SelectorFromSet(fieldSet).String()
+	}
+
+	if namespace == "" {
+		namespace = corev1.NamespaceAll
+	}
+
+	events, err := c.clientset.CoreV1().Events(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(events.Items, func(i, j int) bool {
+		return EventTime(&events.Items[i]).Before(EventTime(&events.Items[j]))
+	})
+
+	return events, nil
+}
+
+// EventTime returns the time an event was last observed
+func EventTime(event *corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
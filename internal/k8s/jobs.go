@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ListJobs lists jobs in a namespace with optional label selector
+func (c *Client) ListJobs(ctx context.Context, namespace string, selector string) (*batchv1.JobList, error) {
+	opts := metav1.ListOptions{}
+
+	if selector != "" {
+		// Validate selector
+		if _, err := labels.Parse(selector); err != nil {
+			return nil, fmt.Errorf("invalid label selector '%s': %w", selector, err)
+		}
+		opts.LabelSelector = selector
+	}
+
+	if namespace == "" {
+		namespace = corev1.NamespaceAll
+	}
+
+	return c.clientset.BatchV1().Jobs(namespace).List(ctx, opts)
+}
+
+// GetJob gets a specific job
+func (c *Client) GetJob(ctx context.Context, namespace, name string) (*batchv1.Job, error) {
+	if namespace == "" {
+		namespace = corev1.NamespaceDefault
+	}
+
+	return c.clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// JobMatchesSelector checks if a job matches the given label selector
+func (c *Client) JobMatchesSelector(ctx context.Context, namespace, jobName, selector string) (bool, error) {
+	if selector == "" {
+		return true, nil
+	}
+
+	job, err := c.GetJob(ctx, namespace, jobName)
+	if err != nil {
+		return false, err
+	}
+
+	labelSelector, err := labels.Parse(selector)
+	if err != nil {
+		return false, fmt.Errorf("invalid selector: %w", err)
+	}
+
+	return labelSelector.Matches(labels.Set(job.Labels)), nil
+}
+
+// JobStatus returns a short status for a job (Complete, Failed, Suspended or Running)
+func JobStatus(job *batchv1.Job) string {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return "Complete"
+		case batchv1.JobFailed:
+			return "Failed"
+		case batchv1.JobSuspended:
+			return "Suspended"
+		}
+	}
+	return "Running"
+}
@@ -0,0 +1,136 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Log levels understood by LogFilter, from least to most severe
+const (
+	LevelUnknown = iota
+	LevelWarn
+	LevelError
+)
+
+// LogFilter selects log lines by regular expression and severity
+type LogFilter struct {
+	Pattern  *regexp.Regexp // Keep lines matching this pattern
+	Invert   bool           // Keep lines not matching Pattern instead
+	MinLevel int            // Keep lines at or above this level (LevelUnknown keeps all)
+}
+
+// ParseLogLevel converts a --level value to a minimum log level
+func ParseLogLevel(level string) (int, error) {
+	switch strings.ToLower(level) {
+	case "error", "err":
+		return LevelError, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	default:
+		return LevelUnknown, fmt.Errorf("unsupported log level '%s' (use error or warn)", level)
+	}
+}
+
+// IsEmpty reports whether the filter keeps every line
+func (f LogFilter) IsEmpty() bool {
+	return f.Pattern == nil && f.MinLevel == LevelUnknown
+}
+
+// Matches reports whether a single log line passes the filter
+func (f LogFilter) Matches(line string) bool {
+	if f.Pattern != nil && f.Pattern.MatchString(line) == f.Invert {
+		return false
+	}
+	if f.MinLevel != LevelUnknown && LineLevel(line) < f.MinLevel {
+		return false
+	}
+	return true
+}
+
+// Apply returns the lines of logs that pass the filter
+func (f LogFilter) Apply(logs string) string {
+	if f.IsEmpty() {
+		return logs
+	}
+
+	var builder strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(logs, "\n"), "\n") {
+		if line != "" && f.Matches(line) {
+			builder.WriteString(line)
+			builder.WriteString("\n")
+		}
+	}
+	return builder.String()
+}
+
+var (
+	// klogLevelPattern matches klog/glog headers such as "E0102 15:04:05.000000"
+	klogLevelPattern = regexp.MustCompile(`(?:^|\s)([EWF])\d{4} \d{2}:\d{2}:\d{2}`)
+
+	// textLevelPattern matches level words in plain-text logs
+	textLevelPattern = regexp.MustCompile(`(?i)\b(fatal|panic|crit|critical|error|err|warn|warning)\b`)
+
+	// jsonLevelKeys are the fields structured loggers use for the level
+	jsonLevelKeys = []string{"level", "lvl", "severity", "log.level", "loglevel", "levelname"}
+)
+
+// LineLevel detects the severity of a log line. JSON lines are checked for
+// common level fields; plain-text lines for klog headers and level words.
+func LineLevel(line string) int {
+	if start := strings.Index(line, "{"); start >= 0 && strings.HasSuffix(strings.TrimSpace(line), "}") {
+		var fields map[string]interface{}
+		if err := json.Unmarshal([]byte(line[start:]), &fields); err == nil {
+			return jsonLevel(fields)
+		}
+	}
+
+	if match := klogLevelPattern.FindStringSubmatch(line); match != nil {
+		if match[1] == "W" {
+			return LevelWarn
+		}
+		return LevelError
+	}
+
+	level := LevelUnknown
+	for _, word := range textLevelPattern.FindAllString(line, -1) {
+		if l := levelFromName(word); l > level {
+			level = l
+		}
+	}
+	return level
+}
+
+// jsonLevel returns the level of a structured log entry
+func jsonLevel(fields map[string]interface{}) int {
+	for _, key := range jsonLevelKeys {
+		switch value := fields[key].(type) {
+		case string:
+			return levelFromName(value)
+		case float64:
+			// Numeric levels as used by pino and bunyan
+			switch {
+			case value >= 50:
+				return LevelError
+			case value >= 40:
+				return LevelWarn
+			default:
+				return LevelUnknown
+			}
+		}
+	}
+	return LevelUnknown
+}
+
+// levelFromName maps a level name to a log level
+func levelFromName(name string) int {
+	switch strings.ToLower(name) {
+	case "error", "err", "fatal", "panic", "crit", "critical", "alert", "emerg", "emergency":
+		return LevelError
+	case "warn", "warning":
+		return LevelWarn
+	default:
+		return LevelUnknown
+	}
+}
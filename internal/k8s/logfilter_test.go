@@ -0,0 +1,68 @@
+package k8s
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestLineLevel(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected int
+	}{
+		{`2024-01-01 10:00:00 ERROR failed to connect`, LevelError},
+		{`[WARN] slow query`, LevelWarn},
+		{`level=warning msg="retrying"`, LevelWarn},
+		{`INFO server started`, LevelUnknown},
+		{`E0102 15:04:05.123456       1 controller.go:42] sync failed`, LevelError},
+		{`W0102 15:04:05.123456       1 controller.go:42] deprecated`, LevelWarn},
+		{`{"level":"error","msg":"boom"}`, LevelError},
+		{`{"severity":"WARNING","message":"disk"}`, LevelWarn},
+		{`{"level":"info","msg":"error count is zero"}`, LevelUnknown},
+		{`{"level":50,"msg":"pino error"}`, LevelError},
+		{`{"level":30,"msg":"pino info"}`, LevelUnknown},
+		{`2024-01-01T10:00:00Z [api-1] {"lvl":"warn","msg":"prefixed json"}`, LevelWarn},
+		{`stderr output redirected`, LevelUnknown},
+	}
+
+	for _, tt := range tests {
+		if result := LineLevel(tt.line); result != tt.expected {
+			t.Errorf("LineLevel(%q) = %d, expected %d", tt.line, result, tt.expected)
+		}
+	}
+}
+
+func TestLogFilter_Apply(t *testing.T) {
+	logs := "INFO start\nWARN slow\nERROR timeout talking to db\nINFO db ok\n"
+
+	tests := []struct {
+		description string
+		filter      LogFilter
+		expected    string
+	}{
+		{"Empty filter", LogFilter{}, logs},
+		{"Grep", LogFilter{Pattern: regexp.MustCompile("db")}, "ERROR timeout talking to db\nINFO db ok\n"},
+		{"Inverted grep", LogFilter{Pattern: regexp.MustCompile("db"), Invert: true}, "INFO start\nWARN slow\n"},
+		{"Warn and above", LogFilter{MinLevel: LevelWarn}, "WARN slow\nERROR timeout talking to db\n"},
+		{"Errors only", LogFilter{MinLevel: LevelError}, "ERROR timeout talking to db\n"},
+		{"Grep and level", LogFilter{Pattern: regexp.MustCompile("slow|ok"), MinLevel: LevelWarn}, "WARN slow\n"},
+	}
+
+	for _, tt := range tests {
+		if result := tt.filter.Apply(logs); result != tt.expected {
+			t.Errorf("%s: Apply() = %q, expected %q", tt.description, result, tt.expected)
+		}
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	if level, err := ParseLogLevel("error"); err != nil || level != LevelError {
+		t.Errorf("ParseLogLevel(error) = %d, %v", level, err)
+	}
+	if level, err := ParseLogLevel("WARNING"); err != nil || level != LevelWarn {
+		t.Errorf("ParseLogLevel(WARNING) = %d, %v", level, err)
+	}
+	if _, err := ParseLogLevel("debug"); err == nil {
+		t.Error("ParseLogLevel(debug) expected error")
+	}
+}
@@ -0,0 +1,138 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxLogBytes caps how much log output GetPodLogs keeps in memory. Older
+// output beyond the cap is dropped so the most recent lines are always kept.
+const DefaultMaxLogBytes = 8 << 20
+
+// LogReadError reports that a log stream failed after it was opened, as opposed
+// to ending normally. Output read before the failure is still usable.
+type LogReadError struct {
+	Err error
+}
+
+func (e *LogReadError) Error() string {
+	return fmt.Sprintf("log stream interrupted: %v", e.Err)
+}
+
+func (e *LogReadError) Unwrap() error {
+	return e.Err
+}
+
+// LogReader streams a pod's logs. Read returns io.EOF only when the stream ended
+// normally; any other failure is wrapped in a *LogReadError.
+type LogReader struct {
+	stream io.ReadCloser
+}
+
+// NewLogReader wraps an open log stream
+func NewLogReader(stream io.ReadCloser) *LogReader {
+	return &LogReader{stream: stream}
+}
+
+// Read implements io.Reader
+func (r *LogReader) Read(p []byte) (int, error) {
+	n, err := r.stream.Read(p)
+	if err != nil && err != io.EOF {
+		err = &LogReadError{Err: err}
+	}
+	return n, err
+}
+
+// Close closes the underlying stream
+func (r *LogReader) Close() error {
+	return r.stream.Close()
+}
+
+// TailBuffer is an io.Writer that keeps only the last max bytes written to it in
+// a fixed-size ring, so memory stays bounded no matter how much is written
+type TailBuffer struct {
+	data    []byte
+	max     int
+	start   int // index of the oldest byte once data is full
+	dropped int64
+}
+
+// NewTailBuffer creates a buffer that keeps at most max bytes
+func NewTailBuffer(max int) *TailBuffer {
+	return &TailBuffer{max: max}
+}
+
+// Write implements io.Writer. It never fails.
+func (t *TailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+
+	if t.max <= 0 {
+		t.dropped += int64(n)
+		return n, nil
+	}
+
+	// p alone fills the buffer: keep its tail and drop everything else
+	if len(p) >= t.max {
+		t.dropped += int64(len(t.data) + len(p) - t.max)
+		t.data = append(t.data[:0], p[len(p)-t.max:]...)
+		t.start = 0
+		return n, nil
+	}
+
+	if free := t.max - len(t.data); free > 0 {
+		if len(p) <= free {
+			t.data = append(t.data, p...)
+			return n, nil
+		}
+		t.data = append(t.data, p[:free]...)
+		p = p[free:]
+	}
+
+	// The buffer is full: overwrite the oldest bytes
+	for len(p) > 0 {
+		copied := copy(t.data[t.start:], p)
+		t.start = (t.start + copied) % t.max
+		t.dropped += int64(copied)
+		p = p[copied:]
+	}
+
+	return n, nil
+}
+
+// Bytes returns the buffered output, oldest byte first
+func (t *TailBuffer) Bytes() []byte {
+	out := make([]byte, 0, len(t.data))
+	out = append(out, t.data[t.start:]...)
+	return append(out, t.data[:t.start]...)
+}
+
+// Dropped returns the number of bytes that were discarded to stay within the cap
+func (t *TailBuffer) Dropped() int64 {
+	return t.dropped
+}
+
+// String returns the buffered output. When older output was dropped, the partial
+// first line is removed and a note on how much was dropped is put in its place.
+func (t *TailBuffer) String() string {
+	data := t.Bytes()
+	if t.dropped == 0 {
+		return string(data)
+	}
+
+	dropped := t.dropped
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		dropped += int64(i + 1)
+		data = data[i+1:]
+	}
+
+	return fmt.Sprintf("... %d bytes of earlier output dropped\n%s", dropped, data)
+}
+
+// ReadLogTail reads r to the end and returns at most maxBytes of its most recent
+// output. A read error is returned together with the output read before it.
+func ReadLogTail(r io.Reader, maxBytes int) (string, error) {
+	tail := NewTailBuffer(maxBytes)
+	_, err := io.Copy(tail, r)
+	return tail.String(), err
+}
@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestTailBuffer(t *testing.T) {
+	tests := []struct {
+		name     string
+		max      int
+		writes   []string
+		expected string
+		dropped  int64
+	}{
+		{"Fits", 10, []string{"abc", "def"}, "abcdef", 0},
+		{"Exactly full", 6, []string{"abc", "def"}, "abcdef", 0},
+		{"Wraps", 5, []string{"abc", "def", "gh"}, "defgh", 3},
+		{"Wraps several times", 4, []string{"ab", "cd", "ef", "gh", "i"}, "fghi", 5},
+		{"Single write larger than buffer", 3, []string{"ab", "cdefg"}, "efg", 4},
+		{"Zero size keeps nothing", 0, []string{"abc"}, "", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := NewTailBuffer(tt.max)
+			for _, w := range tt.writes {
+				n, err := buf.Write([]byte(w))
+				if err != nil || n != len(w) {
+					t.Fatalf("Write(%q) = %d, %v", w, n, err)
+				}
+			}
+			if got := string(buf.Bytes()); got != tt.expected {
+				t.Errorf("Bytes() = %q, expected %q", got, tt.expected)
+			}
+			if buf.Dropped() != tt.dropped {
+				t.Errorf("Dropped() = %d, expected %d", buf.Dropped(), tt.dropped)
+			}
+		})
+	}
+}
+
+func TestTailBuffer_String(t *testing.T) {
+	buf := NewTailBuffer(12)
+	buf.Write([]byte("line one\nline two\nline three\n"))
+
+	expected := "... 18 bytes of earlier output dropped\nline three\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("String() = %q, expected %q", got, expected)
+	}
+
+	buf = NewTailBuffer(100)
+	buf.Write([]byte("short\n"))
+	if got := buf.String(); got != "short\n" {
+		t.Errorf("String() = %q, expected output unchanged when nothing was dropped", got)
+	}
+}
+
+func TestReadLogTail(t *testing.T) {
+	logs, err := ReadLogTail(strings.NewReader("a\nb\nc\n"), 100)
+	if err != nil || logs != "a\nb\nc\n" {
+		t.Errorf("ReadLogTail() = %q, %v", logs, err)
+	}
+
+	// A failing stream keeps the output read before the error
+	failing := io.MultiReader(strings.NewReader("a\nb\n"), iotest.ErrReader(errors.New("connection reset")))
+	logs, err = ReadLogTail(NewLogReader(io.NopCloser(failing)), 100)
+	if logs != "a\nb\n" {
+		t.Errorf("ReadLogTail() logs = %q, expected partial output", logs)
+	}
+
+	var readErr *LogReadError
+	if !errors.As(err, &readErr) {
+		t.Fatalf("ReadLogTail() error = %v, expected *LogReadError", err)
+	}
+	if readErr.Err.Error() != "connection reset" {
+		t.Errorf("LogReadError wraps %v, expected the stream error", readErr.Err)
+	}
+}
+
+func TestLogReader_EOF(t *testing.T) {
+	reader := NewLogReader(io.NopCloser(strings.NewReader("")))
+	if _, err := reader.Read(make([]byte, 8)); err != io.EOF {
+		t.Errorf("Read() at end of stream = %v, expected io.EOF", err)
+	}
+}
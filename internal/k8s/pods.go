@@ -3,6 +3,10 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -37,36 +41,179 @@ func (c *Client) GetPod(ctx context.Context, namespace, name string) (*corev1.Po
 	return c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
-// GetPodLogs retrieves logs from a pod
-func (c *Client) GetPodLogs(ctx context.Context, namespace, podName string, tailLines int64) (string, error) {
+// LogOptions selects which logs GetPodLogs returns
+type LogOptions struct {
+	Container    string     // Container name; empty for the pod's only container
+	Previous     bool       // Logs of the previous, terminated container instance
+	SinceSeconds int64      // Only logs newer than this many seconds (0 for no limit)
+	SinceTime    *time.Time // Only logs newer than this time; ignored if SinceSeconds is set
+	Timestamps   bool       // Prefix each line with its RFC3339 timestamp
+	TailLines    int64      // Number of lines from the end to return (0 for all)
+	Follow       bool       // Keep the stream open and return new lines as they are written
+	MaxBytes     int        // Most recent bytes GetPodLogs keeps (0 for DefaultMaxLogBytes)
+}
+
+// PodLogOptions converts the options to the Kubernetes API type
+func (o LogOptions) PodLogOptions() *corev1.PodLogOptions {
+	opts := &corev1.PodLogOptions{
+		Container:  o.Container,
+		Previous:   o.Previous,
+		Timestamps: o.Timestamps,
+		Follow:     o.Follow,
+	}
+
+	if o.TailLines > 0 {
+		tailLines := o.TailLines
+		opts.TailLines = &tailLines
+	}
+
+	if o.SinceSeconds > 0 {
+		sinceSeconds := o.SinceSeconds
+		opts.SinceSeconds = &sinceSeconds
+	} else if o.SinceTime != nil {
+		sinceTime := metav1.NewTime(*o.SinceTime)
+		opts.SinceTime = &sinceTime
+	}
+
+	return opts
+}
+
+// GetPodLogs retrieves logs from a pod, keeping at most MaxBytes of the most recent
+// output. If the stream fails part way, the logs read so far are returned with a
+// *LogReadError.
+func (c *Client) GetPodLogs(ctx context.Context, namespace, podName string, logOpts LogOptions) (string, error) {
+	stream, err := c.StreamPodLogs(ctx, namespace, podName, logOpts)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	maxBytes := logOpts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxLogBytes
+	}
+
+	return ReadLogTail(stream, maxBytes)
+}
+
+// StreamPodLogs opens a log stream for a pod. The caller must close the stream;
+// with Follow set it stays open until the context is cancelled or the container exits.
+func (c *Client) StreamPodLogs(ctx context.Context, namespace, podName string, logOpts LogOptions) (*LogReader, error) {
 	if namespace == "" {
 		namespace = corev1.NamespaceDefault
 	}
 
-	opts := &corev1.PodLogOptions{
-		TailLines: &tailLines,
+	stream, err := c.clientset.CoreV1().Pods(namespace).GetLogs(podName, logOpts.PodLogOptions()).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs: %w", err)
 	}
 
-	req := c.clientset.CoreV1().Pods(namespace).GetLogs(podName, opts)
-	logs, err := req.Stream(ctx)
-	if err != nil {
-		return "", fmt.Errorf("failed to get logs: %w", err)
+	return NewLogReader(stream), nil
+}
+
+// PodLogResult holds the logs or error of one pod in a multi-pod log request
+type PodLogResult struct {
+	Pod  string
+	Logs string
+	Err  error
+}
+
+// maxConcurrentLogRequests limits parallel log requests in GetMultiPodLogs
+const maxConcurrentLogRequests = 5
+
+// GetMultiPodLogs fetches timestamped logs from several pods concurrently. Pods
+// with more than one container use their default container unless one is given.
+// Unless MaxBytes is set, DefaultMaxLogBytes is shared between the pods.
+func (c *Client) GetMultiPodLogs(ctx context.Context, pods []corev1.Pod, logOpts LogOptions) []PodLogResult {
+	results := make([]PodLogResult, len(pods))
+	if logOpts.MaxBytes <= 0 && len(pods) > 0 {
+		logOpts.MaxBytes = DefaultMaxLogBytes / len(pods)
+	}
+	sem := make(chan struct{}, maxConcurrentLogRequests)
+	var wg sync.WaitGroup
+
+	for i := range pods {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			pod := &pods[i]
+			opts := logOpts
+			opts.Timestamps = true
+			if opts.Container == "" {
+				opts.Container = DefaultContainer(pod)
+			}
+
+			logs, err := c.GetPodLogs(ctx, pod.Namespace, pod.Name, opts)
+			results[i] = PodLogResult{Pod: pod.Name, Logs: logs, Err: err}
+		}(i)
 	}
-	defer logs.Close()
 
-	buf := make([]byte, 4096)
-	var result string
-	for {
-		n, err := logs.Read(buf)
-		if n > 0 {
-			result += string(buf[:n])
+	wg.Wait()
+	return results
+}
+
+// DefaultContainer returns the container kubectl would pick for a pod: the
+// kubectl.kubernetes.io/default-container annotation or the first container
+func DefaultContainer(pod *corev1.Pod) string {
+	if name := pod.Annotations["kubectl.kubernetes.io/default-container"]; name != "" {
+		return name
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
+
+// MergePodLogs interleaves timestamped logs from several pods by time and prefixes
+// each line with its pod name. Lines without a timestamp keep the time of the
+// line before them so multi-line entries stay together. Logs read before a pod's
+// stream failed are merged as well.
+func MergePodLogs(results []PodLogResult) string {
+	type logLine struct {
+		time time.Time
+		text string
+	}
+
+	merged := []logLine{}
+	for _, result := range results {
+		if result.Logs == "" {
+			continue
 		}
-		if err != nil {
-			break
+
+		var last time.Time
+		for _, line := range strings.Split(strings.TrimRight(result.Logs, "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+
+			text := line
+			if stamp, rest, found := strings.Cut(line, " "); found {
+				if t, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+					last = t
+					text = rest
+				}
+			}
+
+			merged = append(merged, logLine{
+				time: last,
+				text: fmt.Sprintf("%s [%s] %s", last.UTC().Format(time.RFC3339), result.Pod, text),
+			})
 		}
 	}
 
-	return result, nil
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].time.Before(merged[j].time)
+	})
+
+	var builder strings.Builder
+	for _, line := range merged {
+		builder.WriteString(line.text)
+		builder.WriteString("\n")
+	}
+	return builder.String()
 }
 
 // PodMatchesSelector checks if a pod matches the given label selector
@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestLogOptions_PodLogOptions(t *testing.T) {
+	opts := LogOptions{}.PodLogOptions()
+	if opts.TailLines != nil || opts.SinceSeconds != nil || opts.SinceTime != nil {
+		t.Error("Zero LogOptions should not limit tail or time window")
+	}
+
+	since := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	opts = LogOptions{
+		Container:    "sidecar",
+		Previous:     true,
+		SinceSeconds: 600,
+		SinceTime:    &since,
+		Timestamps:   true,
+		TailLines:    50,
+		Follow:       true,
+	}.PodLogOptions()
+
+	if opts.Container != "sidecar" || !opts.Previous || !opts.Timestamps || !opts.Follow {
+		t.Errorf("Container/previous/timestamps/follow not copied: %+v", opts)
+	}
+	if opts.TailLines == nil || *opts.TailLines != 50 {
+		t.Errorf("TailLines = %v, expected 50", opts.TailLines)
+	}
+	if opts.SinceSeconds == nil || *opts.SinceSeconds != 600 {
+		t.Errorf("SinceSeconds = %v, expected 600", opts.SinceSeconds)
+	}
+	if opts.SinceTime != nil {
+		t.Error("SinceTime should be ignored when SinceSeconds is set")
+	}
+
+	opts = LogOptions{SinceTime: &since}.PodLogOptions()
+	if opts.SinceTime == nil || !opts.SinceTime.Time.Equal(since) {
+		t.Errorf("SinceTime = %v, expected %v", opts.SinceTime, since)
+	}
+}
+
+func TestMergePodLogs(t *testing.T) {
+	results := []PodLogResult{
+		{Pod: "api-1", Logs: "2024-01-01T10:00:01.000000000Z started\n2024-01-01T10:00:03.000000000Z error: boom\n  at main.go:12\n"},
+		{Pod: "api-2", Logs: "2024-01-01T10:00:02.000000000Z started\n"},
+		{Pod: "api-3", Err: errors.New("container not found")},
+	}
+
+	expected := "2024-01-01T10:00:01Z [api-1] started\n" +
+		"2024-01-01T10:00:02Z [api-2] started\n" +
+		"2024-01-01T10:00:03Z [api-1] error: boom\n" +
+		"2024-01-01T10:00:03Z [api-1]   at main.go:12\n"
+
+	if result := MergePodLogs(results); result != expected {
+		t.Errorf("MergePodLogs() =\n%s\nexpected\n%s", result, expected)
+	}
+}
+
+func TestDefaultContainer(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "istio-proxy"}, {Name: "app"}}},
+	}
+	if name := DefaultContainer(pod); name != "istio-proxy" {
+		t.Errorf("DefaultContainer() = %q, expected first container", name)
+	}
+
+	pod.Annotations = map[string]string{"kubectl.kubernetes.io/default-container": "app"}
+	if name := DefaultContainer(pod); name != "app" {
+		t.Errorf("DefaultContainer() = %q, expected annotated container", name)
+	}
+}
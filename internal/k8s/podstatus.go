@@ -0,0 +1,112 @@
+package k8s
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// PodSummary is a kubectl-like view of a pod's status
+type PodSummary struct {
+	Name            string
+	Namespace       string
+	ReadyContainers int
+	TotalContainers int
+	Status          string // Aggregated reason (e.g., Running, CrashLoopBackOff, OOMKilled, Completed)
+	Restarts        int32
+	Age             string
+	Node            string
+}
+
+// Ready returns the ready container count as "ready/total"
+func (s PodSummary) Ready() string {
+	return fmt.Sprintf("%d/%d", s.ReadyContainers, s.TotalContainers)
+}
+
+// SummarizePod computes the status columns shown by `kubectl get pods`
+func SummarizePod(pod *corev1.Pod, now time.Time) PodSummary {
+	summary := PodSummary{
+		Name:            pod.Name,
+		Namespace:       pod.Namespace,
+		TotalContainers: len(pod.Spec.Containers),
+		Node:            pod.Spec.NodeName,
+		Age:             "<unknown>",
+	}
+
+	if !pod.CreationTimestamp.IsZero() {
+		summary.Age = duration.HumanDuration(now.Sub(pod.CreationTimestamp.Time))
+	}
+	if summary.Node == "" {
+		summary.Node = "<none>"
+	}
+
+	reason := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		reason = pod.Status.Reason
+	}
+
+	// Init containers block the pod until they complete
+	initializing := false
+	for i, container := range pod.Status.InitContainerStatuses {
+		summary.Restarts += container.RestartCount
+		switch {
+		case container.State.Terminated != nil && container.State.Terminated.ExitCode == 0:
+			continue
+		case container.State.Terminated != nil:
+			reason = "Init:" + terminatedReason(container.State.Terminated)
+		case container.State.Waiting != nil && container.State.Waiting.Reason != "" && container.State.Waiting.Reason != "PodInitializing":
+			reason = "Init:" + container.State.Waiting.Reason
+		default:
+			reason = fmt.Sprintf("Init:%d/%d", i, len(pod.Spec.InitContainers))
+		}
+		initializing = true
+		break
+	}
+
+	if !initializing {
+		hasRunning := false
+		for i := len(pod.Status.ContainerStatuses) - 1; i >= 0; i-- {
+			container := pod.Status.ContainerStatuses[i]
+			summary.Restarts += container.RestartCount
+
+			switch {
+			case container.State.Waiting != nil && container.State.Waiting.Reason != "":
+				reason = container.State.Waiting.Reason
+			case container.State.Terminated != nil:
+				reason = terminatedReason(container.State.Terminated)
+			case container.Ready && container.State.Running != nil:
+				hasRunning = true
+				summary.ReadyContainers++
+			}
+		}
+
+		// A completed sidecar should not hide running containers
+		if reason == "Completed" && hasRunning {
+			reason = "Running"
+		}
+	}
+
+	if pod.DeletionTimestamp != nil {
+		if pod.Status.Reason == "NodeLost" {
+			reason = "Unknown"
+		} else {
+			reason = "Terminating"
+		}
+	}
+
+	summary.Status = reason
+	return summary
+}
+
+// terminatedReason describes why a container terminated
+func terminatedReason(state *corev1.ContainerStateTerminated) string {
+	if state.Reason != "" {
+		return state.Reason
+	}
+	if state.Signal != 0 {
+		return fmt.Sprintf("Signal:%d", state.Signal)
+	}
+	return fmt.Sprintf("ExitCode:%d", state.ExitCode)
+}
@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSummarizePod(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	created := metav1.NewTime(now.Add(-3 * time.Hour))
+
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	crashLoop := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}
+	completed := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}}
+	oomKilled := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}
+
+	newPod := func(phase corev1.PodPhase, statuses ...corev1.ContainerStatus) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "production", CreationTimestamp: created},
+			Spec:       corev1.PodSpec{NodeName: "node-1"},
+			Status:     corev1.PodStatus{Phase: phase, ContainerStatuses: statuses},
+		}
+		for range statuses {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{})
+		}
+		return pod
+	}
+
+	tests := []struct {
+		description      string
+		pod              *corev1.Pod
+		expectedStatus   string
+		expectedReady    string
+		expectedRestarts int32
+	}{
+		{
+			"Healthy pod",
+			newPod(corev1.PodRunning, corev1.ContainerStatus{Ready: true, State: running}),
+			"Running", "1/1", 0,
+		},
+		{
+			"CrashLoopBackOff hidden behind Running phase",
+			newPod(corev1.PodRunning,
+				corev1.ContainerStatus{Ready: true, State: running},
+				corev1.ContainerStatus{State: crashLoop, RestartCount: 7}),
+			"CrashLoopBackOff", "1/2", 7,
+		},
+		{
+			"OOMKilled container",
+			newPod(corev1.PodRunning, corev1.ContainerStatus{State: oomKilled, RestartCount: 2}),
+			"OOMKilled", "0/1", 2,
+		},
+		{
+			"Completed job pod",
+			newPod(corev1.PodSucceeded, corev1.ContainerStatus{State: completed}),
+			"Completed", "0/1", 0,
+		},
+		{
+			"Completed sidecar next to running container",
+			newPod(corev1.PodRunning,
+				corev1.ContainerStatus{Ready: true, State: running},
+				corev1.ContainerStatus{State: completed}),
+			"Running", "1/2", 0,
+		},
+	}
+
+	for _, tt := range tests {
+		summary := SummarizePod(tt.pod, now)
+
+		if summary.Status != tt.expectedStatus {
+			t.Errorf("%s: status = %q, expected %q", tt.description, summary.Status, tt.expectedStatus)
+		}
+		if summary.Ready() != tt.expectedReady {
+			t.Errorf("%s: ready = %q, expected %q", tt.description, summary.Ready(), tt.expectedReady)
+		}
+		if summary.Restarts != tt.expectedRestarts {
+			t.Errorf("%s: restarts = %d, expected %d", tt.description, summary.Restarts, tt.expectedRestarts)
+		}
+		if summary.Age != "3h" {
+			t.Errorf("%s: age = %q, expected %q", tt.description, summary.Age, "3h")
+		}
+		if summary.Node != "node-1" {
+			t.Errorf("%s: node = %q, expected %q", tt.description, summary.Node, "node-1")
+		}
+	}
+}
+
+func TestSummarizePod_InitAndTerminating(t *testing.T) {
+	now := time.Now()
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{}, {}},
+			Containers:     []corev1.Container{{}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+				{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			},
+		},
+	}
+
+	if summary := SummarizePod(pod, now); summary.Status != "Init:1/2" {
+		t.Errorf("Init pod status = %q, expected %q", summary.Status, "Init:1/2")
+	}
+
+	deleted := metav1.NewTime(now)
+	pod.DeletionTimestamp = &deleted
+	if summary := SummarizePod(pod, now); summary.Status != "Terminating" {
+		t.Errorf("Deleted pod status = %q, expected %q", summary.Status, "Terminating")
+	}
+
+	if summary := SummarizePod(pod, now); summary.Node != "<none>" || summary.Age != "<unknown>" {
+		t.Errorf("Unscheduled pod node/age = %q/%q, expected <none>/<unknown>", summary.Node, summary.Age)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
@@ -36,3 +37,66 @@ func (c *Client) GetService(ctx context.Context, namespace, name string) (*corev
 
 	return c.clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
 }
+
+// ServiceMatchesSelector checks if a service matches the given label selector
+func (c *Client) ServiceMatchesSelector(ctx context.Context, namespace, serviceName, selector string) (bool, error) {
+	if selector == "" {
+		return true, nil
+	}
+
+	service, err := c.GetService(ctx, namespace, serviceName)
+	if err != nil {
+		return false, err
+	}
+
+	labelSelector, err := labels.Parse(selector)
+	if err != nil {
+		return false, fmt.Errorf("invalid selector: %w", err)
+	}
+
+	return labelSelector.Matches(labels.Set(service.Labels)), nil
+}
+
+// CountServiceEndpoints returns the number of ready and total endpoints of a service from its EndpointSlices
+func (c *Client) CountServiceEndpoints(ctx context.Context, namespace, serviceName string) (int, int, error) {
+	if namespace == "" {
+		namespace = corev1.NamespaceDefault
+	}
+
+	endpointSlices, err := c.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{discoveryv1.LabelServiceName: serviceName}.String(),
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list endpoint slices: %w", err)
+	}
+
+	ready, total := 0, 0
+	for _, slice := range endpointSlices.Items {
+		for _, endpoint := range slice.Endpoints {
+			total++
+			// A nil ready condition is interpreted as ready
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				ready++
+			}
+		}
+	}
+
+	return ready, total, nil
+}
+
+// ServiceExternalAddresses returns the external IPs and load balancer ingress addresses of a service
+func ServiceExternalAddresses(service *corev1.Service) []string {
+	addresses := append([]string{}, service.Spec.ExternalIPs...)
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			addresses = append(addresses, ingress.IP)
+		}
+		if ingress.Hostname != "" {
+			addresses = append(addresses, ingress.Hostname)
+		}
+	}
+	if service.Spec.Type == corev1.ServiceTypeExternalName && service.Spec.ExternalName != "" {
+		addresses = append(addresses, service.Spec.ExternalName)
+	}
+	return addresses
+}
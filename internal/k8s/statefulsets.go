@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ListStatefulSets lists statefulsets in a namespace with optional label selector
+func (c *Client) ListStatefulSets(ctx context.Context, namespace string, selector string) (*appsv1.StatefulSetList, error) {
+	opts := metav1.ListOptions{}
+
+	if selector != "" {
+		// Validate selector
+		if _, err := labels.Parse(selector); err != nil {
+			return nil, fmt.Errorf("invalid label selector '%s': %w", selector, err)
+		}
+		opts.LabelSelector = selector
+	}
+
+	if namespace == "" {
+		namespace = corev1.NamespaceAll
+	}
+
+	return c.clientset.AppsV1().StatefulSets(namespace).List(ctx, opts)
+}
+
+// GetStatefulSet gets a specific statefulset
+func (c *Client) GetStatefulSet(ctx context.Context, namespace, name string) (*appsv1.StatefulSet, error) {
+	if namespace == "" {
+		namespace = corev1.NamespaceDefault
+	}
+
+	return c.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// RestartStatefulSet restarts a statefulset by updating its annotation
+func (c *Client) RestartStatefulSet(ctx context.Context, namespace, name string) error {
+	if namespace == "" {
+		namespace = corev1.NamespaceDefault
+	}
+
+	statefulSet, err := c.GetStatefulSet(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	if statefulSet.Spec.Template.Annotations == nil {
+		statefulSet.Spec.Template.Annotations = make(map[string]string)
+	}
+	statefulSet.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = metav1.Now().Format("2006-01-02T15:04:05Z07:00")
+
+	_, err = c.clientset.AppsV1().StatefulSets(namespace).Update(ctx, statefulSet, metav1.UpdateOptions{})
+	return err
+}
+
+// ScaleStatefulSet scales a statefulset to the specified number of replicas
+func (c *Client) ScaleStatefulSet(ctx context.Context, namespace, name string, replicas int32) error {
+	if namespace == "" {
+		namespace = corev1.NamespaceDefault
+	}
+
+	statefulSet, err := c.GetStatefulSet(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	statefulSet.Spec.Replicas = &replicas
+	_, err = c.clientset.AppsV1().StatefulSets(namespace).Update(ctx, statefulSet, metav1.UpdateOptions{})
+	return err
+}
+
+// StatefulSetMatchesSelector checks if a statefulset matches the given label selector
+func (c *Client) StatefulSetMatchesSelector(ctx context.Context, namespace, statefulSetName, selector string) (bool, error) {
+	if selector == "" {
+		return true, nil
+	}
+
+	statefulSet, err := c.GetStatefulSet(ctx, namespace, statefulSetName)
+	if err != nil {
+		return false, err
+	}
+
+	labelSelector, err := labels.Parse(selector)
+	if err != nil {
+		return false, fmt.Errorf("invalid selector: %w", err)
+	}
+
+	return labelSelector.Matches(labels.Set(statefulSet.Labels)), nil
+}
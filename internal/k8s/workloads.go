@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkloadSelector returns the pod label selector of a deployment, statefulset or daemonset
+func (c *Client) WorkloadSelector(ctx context.Context, namespace, resource, name string) (string, error) {
+	var selector *metav1.LabelSelector
+
+	switch resource {
+	case "deployments":
+		deployment, err := c.GetDeployment(ctx, namespace, name)
+		if err != nil {
+			return "", err
+		}
+		selector = deployment.Spec.Selector
+	case "statefulsets":
+		statefulSet, err := c.GetStatefulSet(ctx, namespace, name)
+		if err != nil {
+			return "", err
+		}
+		selector = statefulSet.Spec.Selector
+	case "daemonsets":
+		daemonSet, err := c.GetDaemonSet(ctx, namespace, name)
+		if err != nil {
+			return "", err
+		}
+		selector = daemonSet.Spec.Selector
+	default:
+		return "", fmt.Errorf("unsupported workload type: %s", resource)
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return "", fmt.Errorf("invalid workload selector: %w", err)
+	}
+	if labelSelector.Empty() {
+		return "", fmt.Errorf("workload %s/%s has an empty selector", resource, name)
+	}
+
+	return labelSelector.String(), nil
+}
+
+// WorkloadReplicas returns the desired number of pods of a deployment, statefulset or daemonset
+func (c *Client) WorkloadReplicas(ctx context.Context, namespace, resource, name string) (int32, error) {
+	switch resource {
+	case "deployments":
+		deployment, err := c.GetDeployment(ctx, namespace, name)
+		if err != nil {
+			return 0, err
+		}
+		return desiredReplicas(deployment.Spec.Replicas), nil
+	case "statefulsets":
+		statefulSet, err := c.GetStatefulSet(ctx, namespace, name)
+		if err != nil {
+			return 0, err
+		}
+		return desiredReplicas(statefulSet.Spec.Replicas), nil
+	case "daemonsets":
+		daemonSet, err := c.GetDaemonSet(ctx, namespace, name)
+		if err != nil {
+			return 0, err
+		}
+		return daemonSet.Status.DesiredNumberScheduled, nil
+	default:
+		return 0, fmt.Errorf("unsupported workload type: %s", resource)
+	}
+}
+
+// desiredReplicas returns the replica count of a spec, which defaults to 1 when unset
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
@@ -0,0 +1,53 @@
+package rbac
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewElevation builds an elevation of target in namespace lasting d from now.
+// Target is a role name, which grants the role's verbs on all resources, or
+// "verb resource" for a single verb.
+func NewElevation(target, namespace, reason string, now time.Time, d time.Duration) (Elevation, error) {
+	resources, verbs, err := ElevationScope(target)
+	if err != nil {
+		return Elevation{}, err
+	}
+	if strings.TrimSpace(reason) == "" {
+		return Elevation{}, fmt.Errorf("a reason is required")
+	}
+
+	expiresAt := metav1.NewTime(now.Add(d))
+	return Elevation{
+		Target: target,
+		Reason: reason,
+		Permission: Permission{
+			Namespace: namespace,
+			Resources: resources,
+			Verbs:     verbs,
+			ExpiresAt: &expiresAt,
+		},
+		GrantedAt: metav1.NewTime(now),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// ElevationScope returns the resources and verbs an elevation target grants
+func ElevationScope(target string) ([]string, []string, error) {
+	fields := strings.Fields(target)
+	switch len(fields) {
+	case 1:
+		verbs, ok := RoleVerbs(fields[0])
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown role '%s' (expected viewer, operator or admin)", fields[0])
+		}
+		return []string{"*"}, verbs, nil
+	case 2:
+		return []string{fields[1]}, []string{fields[0]}, nil
+	default:
+		return nil, nil, fmt.Errorf("invalid target '%s': expected a role or '<verb> <resource>'", target)
+	}
+}
@@ -0,0 +1,101 @@
+package rbac
+
+import (
+	"testing"
+	"time"
+)
+
+func TestElevationScope(t *testing.T) {
+	tests := []struct {
+		target    string
+		resources []string
+		verbs     []string
+		wantErr   bool
+	}{
+		{"admin", []string{"*"}, []string{"*"}, false},
+		{"viewer", []string{"*"}, []string{"get", "list", "logs"}, false},
+		{"scale deployments", []string{"deployments"}, []string{"scale"}, false},
+		{"superuser", nil, nil, true},
+		{"restart deployments now", nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			resources, verbs, err := ElevationScope(tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ElevationScope(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(resources) != len(tt.resources) || resources[0] != tt.resources[0] {
+				t.Errorf("resources = %v, expected %v", resources, tt.resources)
+			}
+			if len(verbs) != len(tt.verbs) || verbs[0] != tt.verbs[0] {
+				t.Errorf("verbs = %v, expected %v", verbs, tt.verbs)
+			}
+		})
+	}
+}
+
+func TestNewElevation(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	elevation, err := NewElevation("operator", "production", "INC-42", now, time.Hour)
+	if err != nil {
+		t.Fatalf("NewElevation() error = %v", err)
+	}
+
+	if elevation.Permission.Namespace != "production" || elevation.Permission.ExpiresAt == nil {
+		t.Fatalf("Permission = %+v", elevation.Permission)
+	}
+	if !elevation.Permission.ExpiresAt.Time.Equal(now.Add(time.Hour)) || !elevation.ExpiresAt.Time.Equal(now.Add(time.Hour)) {
+		t.Errorf("Expected elevation and its permission to expire at %v", now.Add(time.Hour))
+	}
+	if !contains(elevation.Permission.Verbs, "restart") || contains(elevation.Permission.Verbs, "elevate") {
+		t.Errorf("Verbs = %v, expected operator verbs", elevation.Permission.Verbs)
+	}
+
+	if _, err := NewElevation("operator", "production", "  ", now, time.Hour); err == nil {
+		t.Error("Expected error for missing reason")
+	}
+}
+
+func TestSplitEndedElevations(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ended, _ := NewElevation("admin", "production", "outage", now.Add(-2*time.Hour), time.Hour)
+	ended.CreatedUser = true
+	active, _ := NewElevation("viewer", "staging", "debugging", now, time.Hour)
+
+	kept, removed := splitEndedElevations([]Elevation{ended, active}, now)
+	if len(kept) != 1 || kept[0].Target != "viewer" {
+		t.Errorf("kept = %+v, expected only the active elevation", kept)
+	}
+	if len(removed) != 1 || removed[0].Target != "admin" {
+		t.Errorf("removed = %+v, expected only the ended elevation", removed)
+	}
+	if !createdByElevation(removed) || createdByElevation(kept) {
+		t.Error("createdByElevation() should only report the elevation that created the object")
+	}
+
+	// The entry an elevation added expires together with it
+	_, expired := splitExpired([]Permission{ended.Permission, active.Permission}, now)
+	if len(expired) != 1 || expired[0].Namespace != "production" {
+		t.Errorf("expired = %+v, expected the ended elevation's entry", expired)
+	}
+}
+
+func TestDeepCopy_Elevation(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	elevation, _ := NewElevation("scale deployments", "production", "spike", now, time.Hour)
+	spec := TelegramBotPermissionSpec{Elevations: []Elevation{elevation}}
+
+	var copied TelegramBotPermissionSpec
+	spec.DeepCopyInto(&copied)
+	copied.Elevations[0].Permission.Verbs[0] = "restart"
+	copied.Elevations[0].Permission.ExpiresAt.Time = now
+
+	if spec.Elevations[0].Permission.Verbs[0] != "scale" || spec.Elevations[0].Permission.ExpiresAt.Time.Equal(now) {
+		t.Error("DeepCopyInto should not share elevation data with the original")
+	}
+}
@@ -4,15 +4,24 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"kubectl-bot/internal/config"
 	"kubectl-bot/internal/k8s"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// roleDefaultsAnnotation marks permission objects written by a version in which
+// role defaults apply to the explicit entries. Older versions gave every user the
+// viewer role without it meaning anything; MigrateViewerRoles removes that role
+// from objects without the annotation.
+const roleDefaultsAnnotation = "kbot.go.mamad.dev/role-defaults"
+
 type Manager struct {
 	k8sClient *k8s.Client
 	config    *config.Config
@@ -48,6 +57,7 @@ func (m *Manager) GetUserPermission(ctx context.Context, userID int64) (*Telegra
 
 // CreateUserPermission creates a new TelegramBotPermission
 func (m *Manager) CreateUserPermission(ctx context.Context, permission *TelegramBotPermission) error {
+	markRoleDefaults(permission)
 	unstructuredObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(permission)
 	if err != nil {
 		return fmt.Errorf("failed to convert to unstructured: %w", err)
@@ -65,6 +75,7 @@ func (m *Manager) CreateUserPermission(ctx context.Context, permission *Telegram
 
 // UpdateUserPermission updates an existing TelegramBotPermission
 func (m *Manager) UpdateUserPermission(ctx context.Context, permission *TelegramBotPermission) error {
+	markRoleDefaults(permission)
 	unstructuredObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(permission)
 	if err != nil {
 		return fmt.Errorf("failed to convert to unstructured: %w", err)
@@ -80,57 +91,287 @@ func (m *Manager) UpdateUserPermission(ctx context.Context, permission *Telegram
 	return err
 }
 
-// GrantPermission grants a specific permission to a user
-func (m *Manager) GrantPermission(ctx context.Context, userID int64, namespace, resource, verb, selector string) error {
-	// Get existing permission or create new one
+// GrantPermission grants a specific permission to a user. Non-empty names restrict
+// the grant to resources with matching names, and a non-nil expiresAt makes it
+// time-bound.
+func (m *Manager) GrantPermission(ctx context.Context, userID int64, namespace, resource, verb, selector string, names []string, expiresAt *time.Time) error {
+	// Add new permission
+	newPerm := Permission{
+		Namespace:     namespace,
+		Resources:     []string{resource},
+		Verbs:         []string{verb},
+		ResourceNames: names,
+		Selector:      selector,
+	}
+	if expiresAt != nil {
+		expires := metav1.NewTime(*expiresAt)
+		newPerm.ExpiresAt = &expires
+	}
+
+	permission := m.getOrNewUserPermission(ctx, userID)
+	permission.Spec.Permissions = addPermission(permission.Spec.Permissions, newPerm)
+
+	return m.saveUserPermission(ctx, permission)
+}
+
+// Elevate grants the temporary permission of a break-glass elevation and records
+// the elevation next to it. The permission must expire; the grant sweeper
+// reverts it through PruneExpiredPermissions.
+func (m *Manager) Elevate(ctx context.Context, userID int64, elevation Elevation) (*Elevation, error) {
+	if elevation.Permission.ExpiresAt == nil {
+		return nil, fmt.Errorf("elevation must expire")
+	}
+
+	permission := m.getOrNewUserPermission(ctx, userID)
+
+	// Remember whether the object exists only because of this elevation, so the
+	// revert can remove it again instead of leaving an empty user behind
+	elevation.CreatedUser = permission.ObjectMeta.ResourceVersion == ""
+	if elevation.ID == "" {
+		elevation.ID = strconv.FormatInt(elevation.GrantedAt.UnixNano(), 36)
+	}
+
+	permission.Spec.Permissions = addPermission(permission.Spec.Permissions, elevation.Permission)
+	permission.Spec.Elevations = append(permission.Spec.Elevations, elevation)
+
+	if err := m.saveUserPermission(ctx, permission); err != nil {
+		return nil, err
+	}
+	return &elevation, nil
+}
+
+// SetRole changes the role of a user, creating the user's permission object if needed
+func (m *Manager) SetRole(ctx context.Context, userID int64, role string) error {
+	if !IsRole(role) {
+		return fmt.Errorf("unknown role '%s' (expected viewer, operator or admin)", role)
+	}
+
+	permission := m.getOrNewUserPermission(ctx, userID)
+	permission.Spec.Role = role
+
+	return m.saveUserPermission(ctx, permission)
+}
+
+// getOrNewUserPermission returns the user's permission object, or a new object
+// without role or permissions if the user has none yet. New users get no role, so
+// a grant never brings in the defaults of a role nobody chose.
+func (m *Manager) getOrNewUserPermission(ctx context.Context, userID int64) *TelegramBotPermission {
 	permission, err := m.GetUserPermission(ctx, userID)
+	if err == nil {
+		return permission
+	}
+
+	return &TelegramBotPermission{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "kbot.go.mamad.dev/v1",
+			Kind:       "TelegramBotPermission",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: formatUserResourceName(userID),
+		},
+		Spec: TelegramBotPermissionSpec{
+			TelegramUserID: userID,
+			Permissions:    []Permission{},
+		},
+	}
+}
+
+// saveUserPermission creates a permission object that is not stored yet and updates it otherwise
+func (m *Manager) saveUserPermission(ctx context.Context, permission *TelegramBotPermission) error {
+	if permission.ObjectMeta.ResourceVersion == "" {
+		return m.CreateUserPermission(ctx, permission)
+	}
+	return m.UpdateUserPermission(ctx, permission)
+}
+
+// DeleteUserPermission deletes the TelegramBotPermission of a user
+func (m *Manager) DeleteUserPermission(ctx context.Context, userID int64) error {
+	return m.k8sClient.GetDynamicClient().
+		Resource(k8s.TelegramBotPermissionGVR()).
+		Delete(ctx, formatUserResourceName(userID), metav1.DeleteOptions{})
+}
+
+// addPermission merges a new entry into the entry with the same namespace, names
+// and selector. Time-bound entries are kept separate so a temporary grant never
+// extends or shortens another one.
+func addPermission(permissions []Permission, newPerm Permission) []Permission {
+	if newPerm.ExpiresAt == nil {
+		for i, p := range permissions {
+			if p.Namespace == newPerm.Namespace && p.NamespaceSelector == newPerm.NamespaceSelector &&
+				p.Selector == newPerm.Selector && sameStrings(p.ResourceNames, newPerm.ResourceNames) && p.ExpiresAt == nil {
+				// Merge resources and verbs
+				permissions[i].Resources = mergeUnique(p.Resources, newPerm.Resources)
+				permissions[i].Verbs = mergeUnique(p.Verbs, newPerm.Verbs)
+				return permissions
+			}
+		}
+	}
+
+	return append(permissions, newPerm)
+}
+
+// ListUserPermissions lists the TelegramBotPermissions of all users
+func (m *Manager) ListUserPermissions(ctx context.Context) ([]TelegramBotPermission, error) {
+	list, err := m.k8sClient.GetDynamicClient().
+		Resource(k8s.TelegramBotPermissionGVR()).
+		List(ctx, metav1.ListOptions{})
 	if err != nil {
-		// Create new permission with viewer role
-		permission = &TelegramBotPermission{
-			TypeMeta: metav1.TypeMeta{
-				APIVersion: "kbot.go.mamad.dev/v1",
-				Kind:       "TelegramBotPermission",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name: fmt.Sprintf("user-%d", userID),
-			},
-			Spec: TelegramBotPermissionSpec{
-				TelegramUserID: userID,
-				Role:           "viewer",
-				Permissions:    []Permission{},
-			},
+		return nil, err
+	}
+
+	permissions := make([]TelegramBotPermission, 0, len(list.Items))
+	for _, item := range list.Items {
+		var permission TelegramBotPermission
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &permission); err != nil {
+			return nil, fmt.Errorf("failed to convert unstructured to TelegramBotPermission: %w", err)
 		}
+		permissions = append(permissions, permission)
 	}
 
-	// Add new permission
-	newPerm := Permission{
-		Namespace: namespace,
-		Resources: []string{resource},
-		Verbs:     []string{verb},
-		Selector:  selector,
+	return permissions, nil
+}
+
+// MigrateViewerRoles removes the viewer role from permission objects written by
+// versions before role defaults applied, which gave every user that role, and
+// marks them with roleDefaultsAnnotation. It returns the users whose role was removed.
+func (m *Manager) MigrateViewerRoles(ctx context.Context) ([]int64, error) {
+	permissions, err := m.ListUserPermissions(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// Check if similar permission exists and merge
-	merged := false
-	for i, p := range permission.Spec.Permissions {
-		if p.Namespace == namespace && p.Selector == selector {
-			// Merge resources and verbs
-			permission.Spec.Permissions[i].Resources = mergeUnique(p.Resources, newPerm.Resources)
-			permission.Spec.Permissions[i].Verbs = mergeUnique(p.Verbs, newPerm.Verbs)
-			merged = true
-			break
+	cleared := []int64{}
+	var firstErr error
+	for i := range permissions {
+		permission := &permissions[i]
+		if _, ok := permission.Annotations[roleDefaultsAnnotation]; ok {
+			continue
+		}
+
+		wasViewer := migrateViewerRole(permission)
+		if err := m.UpdateUserPermission(ctx, permission); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to migrate the role of user %d: %w", permission.Spec.TelegramUserID, err)
+			}
+			continue
+		}
+		if wasViewer {
+			cleared = append(cleared, permission.Spec.TelegramUserID)
 		}
 	}
 
-	if !merged {
-		permission.Spec.Permissions = append(permission.Spec.Permissions, newPerm)
+	return cleared, firstErr
+}
+
+// migrateViewerRole clears the viewer role of a permission object and reports
+// whether it had one
+func migrateViewerRole(permission *TelegramBotPermission) bool {
+	if permission.Spec.Role != RoleViewer {
+		return false
 	}
+	permission.Spec.Role = ""
+	return true
+}
 
-	// Update or create
-	if permission.ObjectMeta.ResourceVersion == "" {
-		return m.CreateUserPermission(ctx, permission)
+// markRoleDefaults sets roleDefaultsAnnotation, so MigrateViewerRoles leaves the object alone
+func markRoleDefaults(permission *TelegramBotPermission) {
+	if permission.Annotations == nil {
+		permission.Annotations = map[string]string{}
 	}
-	return m.UpdateUserPermission(ctx, permission)
+	permission.Annotations[roleDefaultsAnnotation] = "true"
+}
+
+// ExpiredGrants lists the permission entries and elevations removed from one
+// user by PruneExpiredPermissions
+type ExpiredGrants struct {
+	TelegramUserID int64
+	Permissions    []Permission
+	Elevations     []Elevation
+}
+
+// PruneExpiredPermissions removes expired entries and elevations from every user's
+// permissions and returns what was removed. A permission object created by an
+// elevation is deleted once nothing else is left in it. Users that fail to
+// update are retried on the next call.
+func (m *Manager) PruneExpiredPermissions(ctx context.Context, now time.Time) ([]ExpiredGrants, error) {
+	permissions, err := m.ListUserPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pruned := []ExpiredGrants{}
+	var firstErr error
+	for i := range permissions {
+		permission := &permissions[i]
+
+		kept, expired := splitExpired(permission.Spec.Permissions, now)
+		keptElevations, endedElevations := splitEndedElevations(permission.Spec.Elevations, now)
+		if len(expired) == 0 && len(endedElevations) == 0 {
+			continue
+		}
+
+		permission.Spec.Permissions = kept
+		permission.Spec.Elevations = keptElevations
+
+		var err error
+		if createdByElevation(endedElevations) && len(kept) == 0 && len(keptElevations) == 0 {
+			err = m.DeleteUserPermission(ctx, permission.Spec.TelegramUserID)
+		} else {
+			err = m.UpdateUserPermission(ctx, permission)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to prune permissions of user %d: %w", permission.Spec.TelegramUserID, err)
+			}
+			continue
+		}
+
+		pruned = append(pruned, ExpiredGrants{
+			TelegramUserID: permission.Spec.TelegramUserID,
+			Permissions:    expired,
+			Elevations:     endedElevations,
+		})
+	}
+
+	return pruned, firstErr
+}
+
+// splitEndedElevations separates the elevations that ended at now from the others
+func splitEndedElevations(elevations []Elevation, now time.Time) ([]Elevation, []Elevation) {
+	kept := []Elevation{}
+	ended := []Elevation{}
+	for _, e := range elevations {
+		if e.Expired(now) {
+			ended = append(ended, e)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	return kept, ended
+}
+
+// createdByElevation reports whether one of the elevations created the permission object
+func createdByElevation(elevations []Elevation) bool {
+	for _, e := range elevations {
+		if e.CreatedUser {
+			return true
+		}
+	}
+	return false
+}
+
+// splitExpired separates the entries that are expired at now from the others
+func splitExpired(permissions []Permission, now time.Time) ([]Permission, []Permission) {
+	kept := []Permission{}
+	expired := []Permission{}
+	for _, p := range permissions {
+		if p.Expired(now) {
+			expired = append(expired, p)
+		} else {
+			kept = append(kept, p)
+		}
+	}
+	return kept, expired
 }
 
 // RevokePermission revokes a specific permission from a user
@@ -178,13 +419,54 @@ func (m *Manager) RevokePermission(ctx context.Context, userID int64, namespace,
 // GetPermissionSummary returns a formatted summary of user permissions
 func (m *Manager) GetPermissionSummary(ctx context.Context, userID int64) (string, error) {
 	permission, err := m.GetUserPermission(ctx, userID)
-	if err != nil {
+	if err != nil && !apierrors.IsNotFound(err) {
 		return "", err
 	}
 
-	summary := fmt.Sprintf("User ID: %d\nRole: %s\n\n", permission.Spec.TelegramUserID, permission.Spec.Role)
+	teams, teamErr := m.GetUserTeams(ctx, userID)
+	if teamErr != nil {
+		return "", teamErr
+	}
+
+	if permission == nil {
+		// Users without permissions of their own may still have access through teams
+		if len(teams) == 0 {
+			return "", err
+		}
+		permission = &TelegramBotPermission{Spec: TelegramBotPermissionSpec{TelegramUserID: userID, Role: "none"}}
+	}
+
+	role := permission.Spec.Role
+	if role == "" {
+		role = "none"
+	}
+	summary := fmt.Sprintf("User ID: %d\nRole: %s\n", permission.Spec.TelegramUserID, role)
+	if len(teams) > 0 {
+		names := make([]string, 0, len(teams))
+		for _, team := range teams {
+			names = append(names, team.Name)
+		}
+		summary += fmt.Sprintf("Teams: %s (see /team show <name>)\n", strings.Join(names, ", "))
+	}
+	if verbs, ok := RoleVerbs(permission.Spec.Role); ok && permission.Spec.Role != RoleAdmin {
+		summary += fmt.Sprintf("Role defaults: %v on all resources in the namespaces of the user's and their teams' permissions\n", verbs)
+	}
+	summary += "\n"
+
+	// Deny rules override everything else, so they come first
+	if len(permission.Spec.Deny) > 0 {
+		summary += "Deny rules:\n"
+		for _, d := range permission.Spec.Deny {
+			summary += fmt.Sprintf("• %s\n", DescribeRule(d))
+		}
+		summary += "\n"
+	}
 
 	if len(permission.Spec.Permissions) == 0 {
+		if len(teams) > 0 {
+			summary += "No permissions granted directly"
+			return summary, nil
+		}
 		summary += "No permissions granted"
 		return summary, nil
 	}
@@ -192,14 +474,35 @@ func (m *Manager) GetPermissionSummary(ctx context.Context, userID int64) (strin
 	summary += "Permissions:\n"
 	for i, p := range permission.Spec.Permissions {
 		summary += fmt.Sprintf("%d. Namespace: %s\n", i+1, p.Namespace)
+		if p.NamespaceSelector != "" {
+			summary += fmt.Sprintf("   Namespace selector: %s\n", p.NamespaceSelector)
+		}
 		summary += fmt.Sprintf("   Resources: %v\n", p.Resources)
 		summary += fmt.Sprintf("   Verbs: %v\n", p.Verbs)
+		if len(p.ResourceNames) > 0 {
+			summary += fmt.Sprintf("   Names: %v\n", p.ResourceNames)
+		}
 		if p.Selector != "" {
 			summary += fmt.Sprintf("   Selector: %s\n", p.Selector)
 		}
+		if p.ExpiresAt != nil {
+			state := ""
+			if p.Expired(time.Now()) {
+				state = " (expired)"
+			}
+			summary += fmt.Sprintf("   Expires: %s%s\n", p.ExpiresAt.UTC().Format(time.RFC3339), state)
+		}
 		summary += "\n"
 	}
 
+	if len(permission.Spec.Elevations) > 0 {
+		summary += "Elevations:\n"
+		for _, e := range permission.Spec.Elevations {
+			summary += fmt.Sprintf("• %s in namespace %s until %s\n", e.Target, e.Permission.Namespace, e.ExpiresAt.UTC().Format(time.RFC3339))
+			summary += fmt.Sprintf("   Reason: %s\n", e.Reason)
+		}
+	}
+
 	return summary, nil
 }
 
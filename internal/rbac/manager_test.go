@@ -2,6 +2,9 @@ package rbac
 
 import (
 	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestMergeUnique(t *testing.T) {
@@ -366,3 +369,99 @@ func TestDeepCopy_TelegramBotPermissionList(t *testing.T) {
 		t.Error("DeepCopy of nil should return nil")
 	}
 }
+
+func TestDeepCopy_PermissionExpiresAt(t *testing.T) {
+	expires := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	original := Permission{Namespace: "staging", ExpiresAt: &expires}
+
+	copied := Permission{}
+	original.DeepCopyInto(&copied)
+
+	copied.ExpiresAt.Time = copied.ExpiresAt.Add(time.Hour)
+	if !original.ExpiresAt.Time.Equal(expires.Time) {
+		t.Error("Original ExpiresAt was modified - not a deep copy")
+	}
+}
+
+func TestPermissionExpired(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	expires := metav1.NewTime(now)
+
+	if (Permission{}).Expired(now) {
+		t.Error("Permission without ExpiresAt should never expire")
+	}
+	if (Permission{ExpiresAt: &expires}).Expired(now.Add(-time.Second)) {
+		t.Error("Permission should be valid before ExpiresAt")
+	}
+	if !(Permission{ExpiresAt: &expires}).Expired(now) {
+		t.Error("Permission should be expired at ExpiresAt")
+	}
+}
+
+func TestAddPermission(t *testing.T) {
+	expires := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	permissions := []Permission{
+		{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"get"}},
+	}
+
+	// A permanent grant merges into the permanent entry with the same namespace and selector
+	permissions = addPermission(permissions, Permission{Namespace: "staging", Resources: []string{"deployments"}, Verbs: []string{"list"}})
+	if len(permissions) != 1 || len(permissions[0].Resources) != 2 || len(permissions[0].Verbs) != 2 {
+		t.Fatalf("Expected permanent grant to merge, got %+v", permissions)
+	}
+
+	// A time-bound grant never merges, so it cannot make permanent access expire
+	permissions = addPermission(permissions, Permission{Namespace: "staging", Resources: []string{"deployments"}, Verbs: []string{"restart"}, ExpiresAt: &expires})
+	if len(permissions) != 2 || permissions[0].ExpiresAt != nil {
+		t.Fatalf("Expected time-bound grant as a separate entry, got %+v", permissions)
+	}
+
+	// A permanent grant does not merge into a time-bound entry
+	permissions = addPermission(permissions[1:], Permission{Namespace: "staging", Resources: []string{"deployments"}, Verbs: []string{"scale"}})
+	if len(permissions) != 2 || permissions[1].ExpiresAt != nil {
+		t.Errorf("Expected permanent grant as a separate entry, got %+v", permissions)
+	}
+
+	// A grant restricted to names does not widen or narrow an unrestricted entry
+	permissions = addPermission(permissions, Permission{Namespace: "staging", Resources: []string{"deployments"}, Verbs: []string{"restart"}, ResourceNames: []string{"api-*"}})
+	if len(permissions) != 3 || len(permissions[1].ResourceNames) != 0 {
+		t.Errorf("Expected name-restricted grant as a separate entry, got %+v", permissions)
+	}
+}
+
+func TestSplitExpired(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := metav1.NewTime(now.Add(-time.Hour))
+	future := metav1.NewTime(now.Add(time.Hour))
+
+	kept, expired := splitExpired([]Permission{
+		{Namespace: "a"},
+		{Namespace: "b", ExpiresAt: &past},
+		{Namespace: "c", ExpiresAt: &future},
+	}, now)
+
+	if len(kept) != 2 || kept[0].Namespace != "a" || kept[1].Namespace != "c" {
+		t.Errorf("kept = %+v, expected a and c", kept)
+	}
+	if len(expired) != 1 || expired[0].Namespace != "b" {
+		t.Errorf("expired = %+v, expected b", expired)
+	}
+}
+
+func TestMigrateViewerRole(t *testing.T) {
+	viewer := &TelegramBotPermission{Spec: TelegramBotPermissionSpec{Role: RoleViewer}}
+	if !migrateViewerRole(viewer) || viewer.Spec.Role != "" {
+		t.Errorf("Expected the viewer role to be cleared, got %q", viewer.Spec.Role)
+	}
+
+	operator := &TelegramBotPermission{Spec: TelegramBotPermissionSpec{Role: RoleOperator}}
+	if migrateViewerRole(operator) || operator.Spec.Role != RoleOperator {
+		t.Errorf("Expected the operator role to be kept, got %q", operator.Spec.Role)
+	}
+
+	markRoleDefaults(viewer)
+	if viewer.Annotations[roleDefaultsAnnotation] != "true" {
+		t.Errorf("annotations = %v, expected %s", viewer.Annotations, roleDefaultsAnnotation)
+	}
+}
@@ -0,0 +1,93 @@
+package rbac
+
+// Role names
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+// roleVerbs lists the verbs each role grants. Viewers and operators get them on
+// every resource in the namespaces of their permission entries; admins get
+// everything everywhere.
+var roleVerbs = map[string][]string{
+	RoleViewer:   {"get", "list", "logs"},
+	RoleOperator: {"get", "list", "logs", "restart", "rollback", "scale"},
+	RoleAdmin:    {"*"},
+}
+
+// RoleVerbs returns the verbs a role grants and whether the role exists
+func RoleVerbs(role string) ([]string, bool) {
+	verbs, ok := roleVerbs[role]
+	if !ok {
+		return nil, false
+	}
+	return append([]string(nil), verbs...), true
+}
+
+// IsRole checks if name is a known role
+func IsRole(name string) bool {
+	_, ok := roleVerbs[name]
+	return ok
+}
+
+// EffectivePermissions returns the explicit permission entries of a user followed
+// by the defaults of the user's role. The role's verbs apply to all resources,
+// scoped to the namespaces, names, selector and expiry of each explicit entry, so a role
+// never reaches beyond the namespaces the user was granted.
+func EffectivePermissions(spec TelegramBotPermissionSpec) []Permission {
+	verbs, ok := roleVerbs[spec.Role]
+	if !ok || spec.Role == RoleAdmin {
+		return spec.Permissions
+	}
+
+	effective := append([]Permission{}, spec.Permissions...)
+	for _, p := range spec.Permissions {
+		if containsRoleDefault(effective[len(spec.Permissions):], p) {
+			continue
+		}
+		effective = append(effective, Permission{
+			Namespace:         p.Namespace,
+			NamespaceSelector: p.NamespaceSelector,
+			Resources:         []string{"*"},
+			Verbs:             verbs,
+			ResourceNames:     p.ResourceNames,
+			Selector:          p.Selector,
+			ExpiresAt:         p.ExpiresAt,
+		})
+	}
+
+	return effective
+}
+
+// containsRoleDefault checks if a role default with the scope of p was already added
+func containsRoleDefault(defaults []Permission, p Permission) bool {
+	for _, d := range defaults {
+		if d.Namespace == p.Namespace && d.NamespaceSelector == p.NamespaceSelector && d.Selector == p.Selector &&
+			sameStrings(d.ResourceNames, p.ResourceNames) && sameExpiry(d, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameExpiry checks if two permission entries expire at the same time
+func sameExpiry(a, b Permission) bool {
+	if a.ExpiresAt == nil || b.ExpiresAt == nil {
+		return a.ExpiresAt == nil && b.ExpiresAt == nil
+	}
+	return a.ExpiresAt.Equal(b.ExpiresAt)
+}
+
+// sameStrings checks if two string slices hold the same values in the same order
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
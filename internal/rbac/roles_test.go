@@ -0,0 +1,108 @@
+package rbac
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEffectivePermissions(t *testing.T) {
+	expires := metav1.NewTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	spec := TelegramBotPermissionSpec{
+		Role: RoleOperator,
+		Permissions: []Permission{
+			{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"logs"}},
+			{Namespace: "staging", Resources: []string{"cronjobs"}, Verbs: []string{"trigger"}},
+			{Namespace: "production", Resources: []string{"pods"}, Verbs: []string{"logs"}, Selector: "app=api", ExpiresAt: &expires},
+		},
+	}
+
+	effective := EffectivePermissions(spec)
+
+	// Two explicit entries share a scope, so there is one default per scope
+	if len(effective) != 5 {
+		t.Fatalf("Expected 3 explicit entries and 2 role defaults, got %d: %+v", len(effective), effective)
+	}
+
+	staging := effective[3]
+	if staging.Namespace != "staging" || !contains(staging.Resources, "deployments") || !contains(staging.Verbs, "restart") {
+		t.Errorf("Staging default = %+v, expected operator verbs on all resources", staging)
+	}
+
+	production := effective[4]
+	if production.Selector != "app=api" || production.ExpiresAt == nil || !production.ExpiresAt.Equal(&expires) {
+		t.Errorf("Production default = %+v, expected the selector and expiry of its entry", production)
+	}
+}
+
+func TestEffectivePermissions_Viewer(t *testing.T) {
+	spec := TelegramBotPermissionSpec{
+		Role:        RoleViewer,
+		Permissions: []Permission{{Namespace: "staging", Resources: []string{"deployments"}, Verbs: []string{"scale"}}},
+	}
+
+	now := time.Now()
+	tests := []struct {
+		check    PermissionCheck
+		expected bool
+	}{
+		{PermissionCheck{Namespace: "staging", Resource: "deployments", Verb: "scale"}, true},
+		{PermissionCheck{Namespace: "staging", Resource: "services", Verb: "list"}, true},
+		{PermissionCheck{Namespace: "staging", Resource: "pods", Verb: "logs"}, true},
+		{PermissionCheck{Namespace: "staging", Resource: "pods", Verb: "restart"}, false},
+		{PermissionCheck{Namespace: "production", Resource: "pods", Verb: "list"}, false},
+	}
+
+	for _, tt := range tests {
+		allowed := false
+		for _, perm := range EffectivePermissions(spec) {
+			if !perm.Expired(now) && matchesPermission(perm, tt.check) {
+				allowed = true
+			}
+		}
+		if allowed != tt.expected {
+			t.Errorf("%s %s in %s: allowed = %v, expected %v", tt.check.Verb, tt.check.Resource, tt.check.Namespace, allowed, tt.expected)
+		}
+	}
+}
+
+func TestEffectivePermissions_NamespaceSelector(t *testing.T) {
+	spec := TelegramBotPermissionSpec{
+		Role:        RoleViewer,
+		Permissions: []Permission{{Namespace: "*", NamespaceSelector: "team=payments", Resources: []string{"pods"}, Verbs: []string{"logs"}}},
+	}
+
+	effective := EffectivePermissions(spec)
+	if len(effective) != 2 || effective[1].NamespaceSelector != "team=payments" {
+		t.Errorf("Expected the role default to keep the namespace selector, got %+v", effective)
+	}
+}
+
+func TestEffectivePermissions_NoDefaults(t *testing.T) {
+	permissions := []Permission{{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"logs"}}}
+
+	for _, role := range []string{RoleAdmin, "", "custom"} {
+		effective := EffectivePermissions(TelegramBotPermissionSpec{Role: role, Permissions: permissions})
+		if len(effective) != 1 {
+			t.Errorf("Role %q: expected only the explicit entry, got %+v", role, effective)
+		}
+	}
+}
+
+func TestRoleVerbs(t *testing.T) {
+	verbs, ok := RoleVerbs(RoleViewer)
+	if !ok || len(verbs) != 3 {
+		t.Errorf("RoleVerbs(viewer) = %v, %v", verbs, ok)
+	}
+
+	// The returned slice must not alias the preset
+	verbs[0] = "restart"
+	if again, _ := RoleVerbs(RoleViewer); again[0] != "get" {
+		t.Error("RoleVerbs should return a copy")
+	}
+
+	if _, ok := RoleVerbs("superuser"); ok || IsRole("superuser") {
+		t.Error("Unknown role should not exist")
+	}
+}
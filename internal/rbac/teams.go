@@ -0,0 +1,381 @@
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"kubectl-bot/internal/k8s"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// teamMemberLabelPrefix starts the label that marks each member of a team, so
+// the teams of a user are found with a label selector instead of listing all teams.
+// The bot sets the labels when it changes a team. A member added with kubectl gets
+// no team access until IndexTeamMembers labels them, which the grant sweeper runs
+// every minute.
+const teamMemberLabelPrefix = "kbot.go.mamad.dev/member-"
+
+// GetTeam retrieves a TelegramBotTeam by name
+func (m *Manager) GetTeam(ctx context.Context, name string) (*TelegramBotTeam, error) {
+	unstructuredObj, err := m.k8sClient.GetDynamicClient().
+		Resource(k8s.TelegramBotTeamGVR()).
+		Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var team TelegramBotTeam
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj.Object, &team); err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured to TelegramBotTeam: %w", err)
+	}
+
+	return &team, nil
+}
+
+// ListTeams lists all TelegramBotTeams
+func (m *Manager) ListTeams(ctx context.Context) ([]TelegramBotTeam, error) {
+	return m.listTeams(ctx, metav1.ListOptions{})
+}
+
+func (m *Manager) listTeams(ctx context.Context, opts metav1.ListOptions) ([]TelegramBotTeam, error) {
+	list, err := m.k8sClient.GetDynamicClient().
+		Resource(k8s.TelegramBotTeamGVR()).
+		List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := make([]TelegramBotTeam, 0, len(list.Items))
+	for _, item := range list.Items {
+		var team TelegramBotTeam
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &team); err != nil {
+			return nil, fmt.Errorf("failed to convert unstructured to TelegramBotTeam: %w", err)
+		}
+		teams = append(teams, team)
+	}
+
+	return teams, nil
+}
+
+// GetUserTeams returns the teams a user is a member of. Only teams carrying the
+// user's member label are read, see IndexTeamMembers. A cluster without the
+// TelegramBotTeam CRD has no teams.
+func (m *Manager) GetUserTeams(ctx context.Context, userID int64) ([]TelegramBotTeam, error) {
+	teams, err := m.listTeams(ctx, metav1.ListOptions{LabelSelector: memberLabel(userID)})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// The labels may be stale after an edit with kubectl: members removed that way
+	// still carry the label, so check the members too. Members added that way are
+	// missing until IndexTeamMembers labels them.
+	return teamsOf(teams, userID), nil
+}
+
+// IndexTeamMembers sets the member labels of teams whose labels do not match their
+// members, such as teams edited with kubectl, and returns how many it updated
+func (m *Manager) IndexTeamMembers(ctx context.Context) (int, error) {
+	teams, err := m.ListTeams(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	updated := 0
+	var errs []string
+	for i := range teams {
+		if !setMemberLabels(&teams[i]) {
+			continue
+		}
+		if err := m.UpdateTeam(ctx, &teams[i]); err != nil {
+			errs = append(errs, fmt.Sprintf("team '%s': %v", teams[i].Name, err))
+			continue
+		}
+		updated++
+	}
+
+	if len(errs) > 0 {
+		return updated, fmt.Errorf("failed to index team members: %s", strings.Join(errs, "; "))
+	}
+	return updated, nil
+}
+
+// CreateTeam creates an empty team
+func (m *Manager) CreateTeam(ctx context.Context, name string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("invalid team name '%s': %s", name, strings.Join(errs, ", "))
+	}
+
+	team := &TelegramBotTeam{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "kbot.go.mamad.dev/v1",
+			Kind:       "TelegramBotTeam",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+
+	obj, err := teamToUnstructured(team)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.k8sClient.GetDynamicClient().
+		Resource(k8s.TelegramBotTeamGVR()).
+		Create(ctx, obj, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("team '%s' already exists", name)
+	}
+	return err
+}
+
+// UpdateTeam updates an existing TelegramBotTeam and its member labels
+func (m *Manager) UpdateTeam(ctx context.Context, team *TelegramBotTeam) error {
+	setMemberLabels(team)
+	obj, err := teamToUnstructured(team)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.k8sClient.GetDynamicClient().
+		Resource(k8s.TelegramBotTeamGVR()).
+		Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
+
+// AddTeamMember adds a user to a team. Adding an existing member is a no-op.
+func (m *Manager) AddTeamMember(ctx context.Context, name string, userID int64) error {
+	team, err := m.GetTeam(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if team.HasMember(userID) {
+		return nil
+	}
+
+	team.Spec.Members = append(team.Spec.Members, userID)
+	return m.UpdateTeam(ctx, team)
+}
+
+// RemoveTeamMember removes a user from a team
+func (m *Manager) RemoveTeamMember(ctx context.Context, name string, userID int64) error {
+	team, err := m.GetTeam(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if !team.HasMember(userID) {
+		return fmt.Errorf("user %d is not a member of team '%s'", userID, name)
+	}
+
+	members := []int64{}
+	for _, member := range team.Spec.Members {
+		if member != userID {
+			members = append(members, member)
+		}
+	}
+
+	team.Spec.Members = members
+	return m.UpdateTeam(ctx, team)
+}
+
+// Access is what decides what a user may do
+type Access struct {
+	Role        string       // Empty if the user only has access through teams
+	Permissions []Permission // Own and team entries followed by the role defaults
+	Sources     []string     // Where each entry of Permissions comes from
+	Deny        []Permission // Deny rules, which override Permissions
+}
+
+// Source describes where the i-th entry of Permissions comes from
+func (a *Access) Source(i int) string {
+	if i < len(a.Sources) {
+		return a.Sources[i]
+	}
+	return "permission entry"
+}
+
+// IsEmpty reports whether the user has neither a role nor any permission entry
+func (a *Access) IsEmpty() bool {
+	return a.Role == "" && len(a.Permissions) == 0
+}
+
+// GetAccess returns the role of a user and every permission entry that applies
+// to them: their own entries, the entries of their teams and the role defaults
+// scoped to both, plus their deny rules. It fails if the user has neither
+// permissions nor a team.
+func (m *Manager) GetAccess(ctx context.Context, userID int64) (*Access, error) {
+	permission, err := m.GetUserPermission(ctx, userID)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	teams, teamErr := m.GetUserTeams(ctx, userID)
+	if teamErr != nil {
+		return nil, teamErr
+	}
+
+	if permission == nil && len(teams) == 0 {
+		return nil, err
+	}
+
+	return combinePermissions(userID, permission, teams), nil
+}
+
+// combinePermissions merges a user's own permissions (nil if they have none) with
+// those of their teams and applies the user's role defaults to the result
+func combinePermissions(userID int64, permission *TelegramBotPermission, teams []TelegramBotTeam) *Access {
+	spec := TelegramBotPermissionSpec{TelegramUserID: userID}
+	if permission != nil {
+		spec = permission.Spec
+	}
+
+	sources := []string{}
+	for range spec.Permissions {
+		sources = append(sources, "own permission entry")
+	}
+	for _, team := range teams {
+		for range team.Spec.Permissions {
+			sources = append(sources, fmt.Sprintf("team '%s'", team.Name))
+		}
+	}
+	spec.Permissions = append(append([]Permission{}, spec.Permissions...), teamPermissions(teams)...)
+
+	effective := EffectivePermissions(spec)
+	for len(sources) < len(effective) {
+		sources = append(sources, fmt.Sprintf("%s role default", spec.Role))
+	}
+
+	return &Access{
+		Role:        spec.Role,
+		Permissions: effective,
+		Sources:     sources,
+		Deny:        spec.Deny,
+	}
+}
+
+// GetTeamSummary returns a formatted summary of a team
+func (m *Manager) GetTeamSummary(ctx context.Context, name string) (string, error) {
+	team, err := m.GetTeam(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	return formatTeamSummary(team), nil
+}
+
+// formatTeamSummary renders the members and permissions of a team
+func formatTeamSummary(team *TelegramBotTeam) string {
+	summary := fmt.Sprintf("Team: %s\n", team.Name)
+
+	if len(team.Spec.Members) == 0 {
+		summary += "Members: none\n"
+	} else {
+		members := make([]string, 0, len(team.Spec.Members))
+		for _, member := range team.Spec.Members {
+			members = append(members, fmt.Sprintf("%d", member))
+		}
+		summary += fmt.Sprintf("Members: %s\n", strings.Join(members, ", "))
+	}
+	summary += "\n"
+
+	if len(team.Spec.Permissions) == 0 {
+		summary += "No permissions granted"
+		return summary
+	}
+
+	summary += "Permissions:\n"
+	for i, p := range team.Spec.Permissions {
+		summary += fmt.Sprintf("%d. Namespace: %s\n", i+1, p.Namespace)
+		if p.NamespaceSelector != "" {
+			summary += fmt.Sprintf("   Namespace selector: %s\n", p.NamespaceSelector)
+		}
+		summary += fmt.Sprintf("   Resources: %v\n", p.Resources)
+		summary += fmt.Sprintf("   Verbs: %v\n", p.Verbs)
+		if len(p.ResourceNames) > 0 {
+			summary += fmt.Sprintf("   Names: %v\n", p.ResourceNames)
+		}
+		if p.Selector != "" {
+			summary += fmt.Sprintf("   Selector: %s\n", p.Selector)
+		}
+		summary += "\n"
+	}
+
+	return summary
+}
+
+// teamsOf returns the teams userID is a member of
+func teamsOf(teams []TelegramBotTeam, userID int64) []TelegramBotTeam {
+	member := []TelegramBotTeam{}
+	for _, team := range teams {
+		if team.HasMember(userID) {
+			member = append(member, team)
+		}
+	}
+	return member
+}
+
+// teamPermissions collects the permission entries of teams
+func teamPermissions(teams []TelegramBotTeam) []Permission {
+	permissions := []Permission{}
+	for _, team := range teams {
+		permissions = append(permissions, team.Spec.Permissions...)
+	}
+	return permissions
+}
+
+// memberLabel is the label that marks userID as a member of a team
+func memberLabel(userID int64) string {
+	return fmt.Sprintf("%s%d", teamMemberLabelPrefix, userID)
+}
+
+// setMemberLabels replaces the member labels of a team with one per member, keeping
+// its other labels, and reports whether anything changed
+func setMemberLabels(team *TelegramBotTeam) bool {
+	labels := map[string]string{}
+	for key, value := range team.Labels {
+		if !strings.HasPrefix(key, teamMemberLabelPrefix) {
+			labels[key] = value
+		}
+	}
+	for _, member := range team.Spec.Members {
+		labels[memberLabel(member)] = "true"
+	}
+
+	changed := len(labels) != len(team.Labels)
+	for key, value := range labels {
+		if current, ok := team.Labels[key]; !ok || current != value {
+			changed = true
+		}
+	}
+
+	if len(labels) == 0 {
+		labels = nil
+	}
+	team.Labels = labels
+	return changed
+}
+
+func teamToUnstructured(team *TelegramBotTeam) (*unstructured.Unstructured, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(team)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert to unstructured: %w", err)
+	}
+
+	result := &unstructured.Unstructured{Object: obj}
+	result.SetGroupVersionKind(k8s.TelegramBotTeamGVR().GroupVersion().WithKind("TelegramBotTeam"))
+	return result, nil
+}
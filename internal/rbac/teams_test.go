@@ -0,0 +1,188 @@
+package rbac
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func newTestTeam(name string, members []int64, permissions ...Permission) TelegramBotTeam {
+	return TelegramBotTeam{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       TelegramBotTeamSpec{Members: members, Permissions: permissions},
+	}
+}
+
+func TestTeamsOf(t *testing.T) {
+	teams := []TelegramBotTeam{
+		newTestTeam("backend", []int64{1, 2}),
+		newTestTeam("frontend", []int64{2, 3}),
+		newTestTeam("empty", nil),
+	}
+
+	tests := []struct {
+		userID   int64
+		expected []string
+	}{
+		{1, []string{"backend"}},
+		{2, []string{"backend", "frontend"}},
+		{4, []string{}},
+	}
+
+	for _, tt := range tests {
+		names := []string{}
+		for _, team := range teamsOf(teams, tt.userID) {
+			names = append(names, team.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("teamsOf(%d) = %v, expected %v", tt.userID, names, tt.expected)
+		}
+	}
+}
+
+func TestCombinePermissions(t *testing.T) {
+	own := &TelegramBotPermission{
+		Spec: TelegramBotPermissionSpec{
+			TelegramUserID: 1,
+			Role:           RoleViewer,
+			Permissions:    []Permission{{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"logs"}}},
+		},
+	}
+	teams := []TelegramBotTeam{
+		newTestTeam("backend", []int64{1}, Permission{Namespace: "production", Resources: []string{"deployments"}, Verbs: []string{"restart"}}),
+	}
+
+	access := combinePermissions(1, own, teams)
+	if access.Role != RoleViewer {
+		t.Errorf("role = %s, expected viewer", access.Role)
+	}
+
+	allowed := func(namespace, resource, verb string) bool {
+		for _, perm := range access.Permissions {
+			if matchesPermission(perm, PermissionCheck{Namespace: namespace, Resource: resource, Verb: verb}) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !allowed("staging", "pods", "logs") || !allowed("production", "deployments", "restart") {
+		t.Error("Expected the union of own and team permissions")
+	}
+	if !allowed("production", "services", "list") {
+		t.Error("Expected viewer defaults in the team's namespace")
+	}
+	if allowed("production", "deployments", "scale") {
+		t.Error("Did not expect scale in production")
+	}
+
+	// Every entry names where it comes from
+	expectedSources := []string{"own permission entry", "team 'backend'", "viewer role default", "viewer role default"}
+	if !reflect.DeepEqual(access.Sources, expectedSources) {
+		t.Errorf("Sources = %v, expected %v", access.Sources, expectedSources)
+	}
+
+	// Deny rules come from the user's own object only
+	own.Spec.Deny = []Permission{{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"logs"}}}
+	if access := combinePermissions(1, own, teams); len(access.Deny) != 1 {
+		t.Errorf("Deny = %+v, expected the user's deny rule", access.Deny)
+	}
+
+	// The user's own entries must not be modified by the merge
+	if len(own.Spec.Permissions) != 1 {
+		t.Errorf("Own permissions changed: %+v", own.Spec.Permissions)
+	}
+}
+
+func TestCombinePermissions_TeamOnly(t *testing.T) {
+	teams := []TelegramBotTeam{
+		newTestTeam("backend", []int64{7}, Permission{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"logs"}}),
+	}
+
+	access := combinePermissions(7, nil, teams)
+	if access.Role != "" {
+		t.Errorf("role = %q, expected none for a team-only user", access.Role)
+	}
+	if len(access.Permissions) != 1 {
+		t.Errorf("permissions = %+v, expected only the team entry", access.Permissions)
+	}
+}
+
+func TestAccessIsEmpty(t *testing.T) {
+	tests := []struct {
+		access   Access
+		expected bool
+	}{
+		{Access{}, true},
+		{Access{Role: RoleAdmin}, false},
+		{Access{Role: RoleViewer}, false},
+		{Access{Permissions: []Permission{{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"logs"}}}}, false},
+	}
+
+	for _, tt := range tests {
+		if tt.access.IsEmpty() != tt.expected {
+			t.Errorf("IsEmpty() of %+v = %v, expected %v", tt.access, !tt.expected, tt.expected)
+		}
+	}
+}
+
+func TestSetMemberLabels(t *testing.T) {
+	team := newTestTeam("backend", []int64{1, 2})
+	team.Labels = map[string]string{"app": "kbot", memberLabel(3): "true"}
+
+	if !setMemberLabels(&team) {
+		t.Error("Expected the labels of a removed and two added members to change")
+	}
+	expected := map[string]string{"app": "kbot", memberLabel(1): "true", memberLabel(2): "true"}
+	if !reflect.DeepEqual(team.Labels, expected) {
+		t.Errorf("labels = %v, expected %v", team.Labels, expected)
+	}
+
+	if setMemberLabels(&team) {
+		t.Error("Expected no change when the labels match the members")
+	}
+
+	team.Spec.Members = nil
+	team.Labels = map[string]string{memberLabel(1): "true"}
+	if !setMemberLabels(&team) || team.Labels != nil {
+		t.Errorf("labels = %v, expected none without members", team.Labels)
+	}
+
+	if errs := validation.IsQualifiedName(memberLabel(1234567890)); len(errs) > 0 {
+		t.Errorf("memberLabel() is not a valid label key: %v", errs)
+	}
+}
+
+func TestFormatTeamSummary(t *testing.T) {
+	team := newTestTeam("backend", []int64{1, 2},
+		Permission{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"logs"}, Selector: "app=api"})
+
+	summary := formatTeamSummary(&team)
+	for _, expected := range []string{"Team: backend", "Members: 1, 2", "Namespace: staging", "Selector: app=api"} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("formatTeamSummary() = %q, expected it to contain %q", summary, expected)
+		}
+	}
+
+	empty := newTestTeam("empty", nil)
+	summary = formatTeamSummary(&empty)
+	if !strings.Contains(summary, "Members: none") || !strings.Contains(summary, "No permissions granted") {
+		t.Errorf("formatTeamSummary() = %q", summary)
+	}
+}
+
+func TestDeepCopy_TelegramBotTeam(t *testing.T) {
+	original := newTestTeam("backend", []int64{1},
+		Permission{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"logs"}})
+
+	copied := original.DeepCopy()
+	copied.Spec.Members[0] = 2
+	copied.Spec.Permissions[0].Verbs[0] = "restart"
+
+	if original.Spec.Members[0] != 1 || original.Spec.Permissions[0].Verbs[0] != "logs" {
+		t.Error("DeepCopy should not share data with the original")
+	}
+}
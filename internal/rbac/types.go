@@ -1,6 +1,8 @@
 package rbac
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -16,16 +18,52 @@ type TelegramBotPermission struct {
 // TelegramBotPermissionSpec defines the desired state of TelegramBotPermission
 type TelegramBotPermissionSpec struct {
 	TelegramUserID int64        `json:"telegramUserId"`
-	Role           string       `json:"role"`
+	Role           string       `json:"role,omitempty"` // Empty for users with only explicit permissions
 	Permissions    []Permission `json:"permissions,omitempty"`
+	Deny           []Permission `json:"deny,omitempty"`
+	Elevations     []Elevation  `json:"elevations,omitempty"`
 }
 
-// Permission defines granular access control
+// Permission defines granular access control.
+// Verbs are read verbs (get, list, logs) or mutating verbs
+// (restart, rollback, scale, trigger, suspend).
+// Namespace is a name, "*" or a glob such as "team-a-*". With NamespaceSelector
+// set, the namespace must also carry matching labels.
+// ResourceNames restricts the entry to objects whose name matches one of the
+// names or globs such as "api-*".
+// A permission with ExpiresAt set is ignored from that time on.
 type Permission struct {
-	Namespace string   `json:"namespace"`
-	Resources []string `json:"resources"`
-	Verbs     []string `json:"verbs"`
-	Selector  string   `json:"selector,omitempty"`
+	Namespace         string       `json:"namespace"`
+	NamespaceSelector string       `json:"namespaceSelector,omitempty"`
+	Resources         []string     `json:"resources"`
+	Verbs             []string     `json:"verbs"`
+	ResourceNames     []string     `json:"resourceNames,omitempty"`
+	Selector          string       `json:"selector,omitempty"`
+	ExpiresAt         *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// Expired reports whether a time-bound permission has expired at now
+func (p Permission) Expired(now time.Time) bool {
+	return p.ExpiresAt != nil && !now.Before(p.ExpiresAt.Time)
+}
+
+// Elevation records a break-glass elevation: the temporary permission entry it
+// added, why, and whether the permission object was created for it, so the
+// elevation can be audited and reverted exactly
+type Elevation struct {
+	ID          string      `json:"id"`
+	Target      string      `json:"target"` // Role name or "verb resource"
+	Reason      string      `json:"reason"`
+	Permission  Permission  `json:"permission"`
+	CreatedUser bool        `json:"createdUser,omitempty"`
+	ApprovedBy  int64       `json:"approvedBy,omitempty"`
+	GrantedAt   metav1.Time `json:"grantedAt"`
+	ExpiresAt   metav1.Time `json:"expiresAt"`
+}
+
+// Expired reports whether an elevation has ended at now
+func (e Elevation) Expired(now time.Time) bool {
+	return !now.Before(e.ExpiresAt.Time)
 }
 
 // TelegramBotPermissionList contains a list of TelegramBotPermission
@@ -68,6 +106,28 @@ func (in *TelegramBotPermissionSpec) DeepCopyInto(out *TelegramBotPermissionSpec
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = make([]Permission, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Elevations != nil {
+		in, out := &in.Elevations, &out.Elevations
+		*out = make([]Elevation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto copies elevation
+func (in *Elevation) DeepCopyInto(out *Elevation) {
+	*out = *in
+	in.Permission.DeepCopyInto(&out.Permission)
+	in.GrantedAt.DeepCopyInto(&out.GrantedAt)
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
 }
 
 // DeepCopyInto copies permission
@@ -83,6 +143,14 @@ func (in *Permission) DeepCopyInto(out *Permission) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ResourceNames != nil {
+		in, out := &in.ResourceNames, &out.ResourceNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpiresAt != nil {
+		out.ExpiresAt = in.ExpiresAt.DeepCopy()
+	}
 }
 
 // DeepCopyInto copies list
@@ -113,3 +181,104 @@ func (in *TelegramBotPermissionList) DeepCopy() *TelegramBotPermissionList {
 func (in *TelegramBotPermissionList) DeepCopyObject() runtime.Object {
 	return in.DeepCopy()
 }
+
+// TelegramBotTeam is the Schema for the telegrambotteams API. Its permissions
+// apply to every member in addition to the member's own permissions.
+type TelegramBotTeam struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TelegramBotTeamSpec `json:"spec,omitempty"`
+}
+
+// TelegramBotTeamSpec defines the members of a team and the permissions they share
+type TelegramBotTeamSpec struct {
+	Members     []int64      `json:"members,omitempty"`
+	Permissions []Permission `json:"permissions,omitempty"`
+}
+
+// HasMember checks if a Telegram user is a member of the team
+func (t *TelegramBotTeam) HasMember(userID int64) bool {
+	for _, member := range t.Spec.Members {
+		if member == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// TelegramBotTeamList contains a list of TelegramBotTeam
+type TelegramBotTeamList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TelegramBotTeam `json:"items"`
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *TelegramBotTeam) DeepCopyInto(out *TelegramBotTeam) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy creates a deep copy
+func (in *TelegramBotTeam) DeepCopy() *TelegramBotTeam {
+	if in == nil {
+		return nil
+	}
+	out := new(TelegramBotTeam)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject creates a deep copy object
+func (in *TelegramBotTeam) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies team spec
+func (in *TelegramBotTeamSpec) DeepCopyInto(out *TelegramBotTeamSpec) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]Permission, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto copies team list
+func (in *TelegramBotTeamList) DeepCopyInto(out *TelegramBotTeamList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TelegramBotTeam, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy creates a deep copy of team list
+func (in *TelegramBotTeamList) DeepCopy() *TelegramBotTeamList {
+	if in == nil {
+		return nil
+	}
+	out := new(TelegramBotTeamList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject creates a deep copy object of team list
+func (in *TelegramBotTeamList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
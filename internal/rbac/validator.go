@@ -3,19 +3,29 @@ package rbac
 import (
 	"context"
 	"fmt"
+	"path"
+	"sort"
 	"strings"
+	"time"
 
 	"kubectl-bot/internal/k8s"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // PermissionCheck defines a permission check request
 type PermissionCheck struct {
 	TelegramUserID int64
 	Namespace      string
-	Resource       string // "pods", "deployments", "services"
-	Verb           string // "get", "list", "logs", "restart", etc.
+	Resource       string // "pods", "deployments", "statefulsets", "daemonsets", "services", "jobs", "cronjobs", "events"
+	Verb           string // "get", "list", "logs", "restart", "trigger", "suspend", etc.
 	ResourceName   string // Specific resource name (e.g., pod name)
 	Selector       string // Optional label selector
+
+	// NamespaceLabels are the labels of Namespace, used by entries with a
+	// namespace selector. The validator looks them up when they are needed.
+	NamespaceLabels map[string]string
 }
 
 // Validator validates permissions
@@ -34,36 +44,85 @@ func NewValidator(manager *Manager, k8sClient *k8s.Client) *Validator {
 
 // CheckPermission validates if a user has permission to perform an action
 func (v *Validator) CheckPermission(ctx context.Context, check PermissionCheck) (bool, string, error) {
+	decision, err := v.Explain(ctx, check)
+	return decision.Allowed, decision.Reason, err
+}
+
+// Decision is the outcome of a permission check and what decided it
+type Decision struct {
+	Allowed bool
+	Reason  string      // Why the check was denied
+	Source  string      // What allowed or denied it, e.g. "bootstrap admin" or "team 'backend'"
+	Rule    *Permission // The permission entry or deny rule that decided, if any
+}
+
+// Explain runs a permission check like CheckPermission and also reports which
+// permission entry, role, bootstrap admin or deny rule decided it
+func (v *Validator) Explain(ctx context.Context, check PermissionCheck) (Decision, error) {
 	// Bootstrap admins have all permissions
 	if v.manager.IsBootstrapAdmin(check.TelegramUserID) {
-		return true, "", nil
+		return Decision{Allowed: true, Source: "bootstrap admin"}, nil
 	}
 
-	// Get user's permissions from CRD
-	permission, err := v.manager.GetUserPermission(ctx, check.TelegramUserID)
+	// Get the user's own and team permissions from CRDs
+	access, err := v.manager.GetAccess(ctx, check.TelegramUserID)
 	if err != nil {
-		return false, fmt.Sprintf("No permissions found for user %d", check.TelegramUserID), err
+		return Decision{Reason: fmt.Sprintf("No permissions found for user %d", check.TelegramUserID)}, err
 	}
 
-	// Admin role has all permissions
-	if permission.Spec.Role == "admin" {
-		return true, "", nil
+	check, err = v.withNamespaceLabels(ctx, check, access)
+	if err != nil {
+		return Decision{Reason: fmt.Sprintf("Failed to read namespace labels: %v", err)}, err
 	}
 
-	// Check each permission entry
-	for _, perm := range permission.Spec.Permissions {
-		// Check namespace (exact match or wildcard)
-		if !matchesNamespace(perm.Namespace, check.Namespace) {
+	// Deny rules override every allow, including the admin role
+	for i, deny := range access.Deny {
+		if _, err := labels.Parse(deny.NamespaceSelector); err != nil {
+			return Decision{Reason: fmt.Sprintf("Invalid namespace selector in deny rule: %s", DescribeRule(deny))}, err
+		}
+		if !matchesPermission(deny, check) {
 			continue
 		}
 
-		// Check resource
-		if !contains(perm.Resources, check.Resource) {
+		// A deny rule with names or a selector only covers named resources matching
+		// them; list operations drop the matching objects instead
+		if len(deny.ResourceNames) > 0 || deny.Selector != "" {
+			if check.ResourceName == "" || !matchesResourceName(deny.ResourceNames, check.ResourceName) {
+				continue
+			}
+		}
+		if deny.Selector != "" {
+			matches, err := v.validateSelector(ctx, check.Namespace, check.Resource, check.ResourceName, deny.Selector)
+			if err != nil {
+				return Decision{Reason: fmt.Sprintf("Failed to validate deny rule selector: %v", err)}, err
+			}
+			if !matches {
+				continue
+			}
+		}
+
+		return Decision{
+			Reason: fmt.Sprintf("Permission denied by deny rule: %s", DescribeRule(deny)),
+			Source: "deny rule",
+			Rule:   &access.Deny[i],
+		}, nil
+	}
+
+	// Admin role has all permissions
+	if access.Role == "admin" {
+		return Decision{Allowed: true, Source: "admin role"}, nil
+	}
+
+	// Check each permission entry and role default; name- and selector-restricted entries are combined as a union
+	scopeReason := ""
+	for i, perm := range access.Permissions {
+		if !matchesPermission(perm, check) {
 			continue
 		}
 
-		// Check verb
-		if !contains(perm.Verbs, check.Verb) {
+		// If permission is restricted to names, the named resource must match one
+		if check.ResourceName != "" && !matchesResourceName(perm.ResourceNames, check.ResourceName) {
+			scopeReason = fmt.Sprintf("Resource '%s' does not match allowed names: %s", check.ResourceName, strings.Join(perm.ResourceNames, ", "))
 			continue
 		}
 
@@ -71,20 +130,233 @@ func (v *Validator) CheckPermission(ctx context.Context, check PermissionCheck)
 		if perm.Selector != "" && check.ResourceName != "" {
 			matches, err := v.validateSelector(ctx, check.Namespace, check.Resource, check.ResourceName, perm.Selector)
 			if err != nil {
-				return false, fmt.Sprintf("Failed to validate selector: %v", err), err
+				return Decision{Reason: fmt.Sprintf("Failed to validate selector: %v", err)}, err
 			}
 			if !matches {
-				return false, fmt.Sprintf("Resource '%s' does not match required selector: %s", check.ResourceName, perm.Selector), nil
+				scopeReason = fmt.Sprintf("Resource '%s' does not match required selector: %s", check.ResourceName, perm.Selector)
+				continue
 			}
 		}
 
 		// Permission granted
-		return true, "", nil
+		return Decision{Allowed: true, Source: access.Source(i), Rule: &access.Permissions[i]}, nil
+	}
+
+	if scopeReason != "" {
+		return Decision{Reason: scopeReason}, nil
 	}
 
 	// No matching permission found
-	return false, fmt.Sprintf("Permission denied: missing '%s' access to %s in namespace '%s'",
-		check.Verb, check.Resource, check.Namespace), nil
+	return Decision{Reason: fmt.Sprintf("Permission denied: missing '%s' access to %s in namespace '%s'",
+		check.Verb, check.Resource, check.Namespace)}, nil
+}
+
+// SelectorSet is the set of object rules a list operation is restricted to.
+// An object is visible if it matches any rule in the set and no denied rule.
+type SelectorSet struct {
+	Allowed      bool         // At least one permission entry grants the verb
+	Unrestricted bool         // An entry without names or selector grants the verb
+	Rules        []ObjectRule // Rules of the name- or selector-restricted entries
+	Denied       []ObjectRule // Rules of deny rules covering the verb
+}
+
+// ObjectRule restricts a permission entry or deny rule to objects with matching
+// names and labels
+type ObjectRule struct {
+	Names    []string        // Names or glob patterns; empty matches every name
+	Selector labels.Selector // Nil matches every object
+}
+
+// Matches reports whether an object with the given name and labels matches the rule
+func (r ObjectRule) Matches(name string, objLabels map[string]string) bool {
+	return matchesResourceName(r.Names, name) &&
+		(r.Selector == nil || r.Selector.Matches(labels.Set(objLabels)))
+}
+
+// Matches reports whether an object with the given name and labels is visible
+func (s SelectorSet) Matches(name string, objLabels map[string]string) bool {
+	if !s.Allowed {
+		return false
+	}
+	for _, rule := range s.Denied {
+		if rule.Matches(name, objLabels) {
+			return false
+		}
+	}
+	if s.Unrestricted {
+		return true
+	}
+
+	for _, rule := range s.Rules {
+		if rule.Matches(name, objLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListSelectors returns the effective selector set for a list operation: the union
+// of the names and selectors of every permission entry granting the verb in the namespace
+func (v *Validator) ListSelectors(ctx context.Context, check PermissionCheck) (SelectorSet, error) {
+	// Bootstrap admins see everything
+	if v.manager.IsBootstrapAdmin(check.TelegramUserID) {
+		return SelectorSet{Allowed: true, Unrestricted: true}, nil
+	}
+
+	access, err := v.manager.GetAccess(ctx, check.TelegramUserID)
+	if err != nil {
+		return SelectorSet{}, err
+	}
+
+	check, err = v.withNamespaceLabels(ctx, check, access)
+	if err != nil {
+		return SelectorSet{}, err
+	}
+
+	// Admin role sees everything that is not denied
+	set := SelectorSet{Allowed: true, Unrestricted: true}
+	if access.Role != "admin" {
+		set, err = selectorSetFor(access.Permissions, check)
+		if err != nil {
+			return SelectorSet{}, err
+		}
+	}
+
+	return applyDenyRules(set, access.Deny, check)
+}
+
+// applyDenyRules restricts a selector set by the deny rules matching a check. A
+// matching rule without names or selector hides everything.
+func applyDenyRules(set SelectorSet, deny []Permission, check PermissionCheck) (SelectorSet, error) {
+	for _, rule := range deny {
+		if _, err := labels.Parse(rule.NamespaceSelector); err != nil {
+			return SelectorSet{}, fmt.Errorf("invalid namespace selector '%s' in deny rule: %w", rule.NamespaceSelector, err)
+		}
+		if !matchesPermission(rule, check) {
+			continue
+		}
+
+		if len(rule.ResourceNames) == 0 && rule.Selector == "" {
+			return SelectorSet{}, nil
+		}
+
+		objectRule, err := objectRuleFor(rule)
+		if err != nil {
+			return SelectorSet{}, fmt.Errorf("invalid selector '%s' in deny rule: %w", rule.Selector, err)
+		}
+		set.Denied = append(set.Denied, objectRule)
+	}
+
+	return set, nil
+}
+
+// withNamespaceLabels fills in the labels of the checked namespace if an entry
+// of access has a namespace selector. A namespace that does not exist has no labels.
+func (v *Validator) withNamespaceLabels(ctx context.Context, check PermissionCheck, access *Access) (PermissionCheck, error) {
+	if check.NamespaceLabels != nil || check.Namespace == "" || !hasNamespaceSelector(access) {
+		return check, nil
+	}
+
+	namespace, err := v.k8sClient.GetNamespace(ctx, check.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			check.NamespaceLabels = map[string]string{}
+			return check, nil
+		}
+		return check, err
+	}
+
+	check.NamespaceLabels = namespaceLabels(namespace.Labels)
+	return check, nil
+}
+
+// hasNamespaceSelector checks if any entry or deny rule of access has a namespace selector
+func hasNamespaceSelector(access *Access) bool {
+	for _, perm := range access.Permissions {
+		if perm.NamespaceSelector != "" {
+			return true
+		}
+	}
+	for _, perm := range access.Deny {
+		if perm.NamespaceSelector != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// DescribeRule renders a permission entry or deny rule in one line
+func DescribeRule(p Permission) string {
+	rule := fmt.Sprintf("%s on %s in namespace '%s'", strings.Join(p.Verbs, ", "), strings.Join(p.Resources, ", "), p.Namespace)
+	if p.NamespaceSelector != "" {
+		rule += fmt.Sprintf(" (namespace selector %s)", p.NamespaceSelector)
+	}
+	if len(p.ResourceNames) > 0 {
+		rule += fmt.Sprintf(" named %s", strings.Join(p.ResourceNames, ", "))
+	}
+	if p.Selector != "" {
+		rule += fmt.Sprintf(" with selector %s", p.Selector)
+	}
+	return rule
+}
+
+// selectorSetFor builds the selector set of the permission entries matching a check
+func selectorSetFor(permissions []Permission, check PermissionCheck) (SelectorSet, error) {
+	set := SelectorSet{}
+	for _, perm := range permissions {
+		if !matchesPermission(perm, check) {
+			continue
+		}
+
+		set.Allowed = true
+		if len(perm.ResourceNames) == 0 && perm.Selector == "" {
+			set.Unrestricted = true
+			continue
+		}
+
+		rule, err := objectRuleFor(perm)
+		if err != nil {
+			return SelectorSet{}, fmt.Errorf("invalid selector '%s' in permission: %w", perm.Selector, err)
+		}
+		set.Rules = append(set.Rules, rule)
+	}
+
+	return set, nil
+}
+
+// objectRuleFor returns the names and parsed selector of a permission entry
+func objectRuleFor(perm Permission) (ObjectRule, error) {
+	rule := ObjectRule{Names: perm.ResourceNames}
+	if perm.Selector != "" {
+		selector, err := labels.Parse(perm.Selector)
+		if err != nil {
+			return ObjectRule{}, err
+		}
+		rule.Selector = selector
+	}
+	return rule, nil
+}
+
+// ListFilter returns a function reporting whether the user may see an object with
+// the given namespace, name and labels when listing resource. Selector sets are cached per
+// namespace and failed lookups count as denied, so list commands can drop objects
+// instead of failing the whole request.
+func (v *Validator) ListFilter(ctx context.Context, userID int64, resource, verb string) func(namespace, name string, objLabels map[string]string) bool {
+	cache := make(map[string]SelectorSet)
+
+	return func(namespace, name string, objLabels map[string]string) bool {
+		set, ok := cache[namespace]
+		if !ok {
+			set, _ = v.ListSelectors(ctx, PermissionCheck{
+				TelegramUserID: userID,
+				Namespace:      namespace,
+				Resource:       resource,
+				Verb:           verb,
+			})
+			cache[namespace] = set
+		}
+		return set.Matches(name, objLabels)
+	}
 }
 
 // validateSelector checks if a resource matches the permission's label selector
@@ -94,20 +366,79 @@ func (v *Validator) validateSelector(ctx context.Context, namespace, resource, r
 		return v.k8sClient.PodMatchesSelector(ctx, namespace, resourceName, selector)
 	case "deployments":
 		return v.k8sClient.DeploymentMatchesSelector(ctx, namespace, resourceName, selector)
+	case "statefulsets":
+		return v.k8sClient.StatefulSetMatchesSelector(ctx, namespace, resourceName, selector)
+	case "daemonsets":
+		return v.k8sClient.DaemonSetMatchesSelector(ctx, namespace, resourceName, selector)
+	case "jobs":
+		return v.k8sClient.JobMatchesSelector(ctx, namespace, resourceName, selector)
+	case "cronjobs":
+		return v.k8sClient.CronJobMatchesSelector(ctx, namespace, resourceName, selector)
 	case "services":
-		// Services don't have selector validation in current implementation
-		return true, nil
+		return v.k8sClient.ServiceMatchesSelector(ctx, namespace, resourceName, selector)
 	default:
 		return false, fmt.Errorf("unsupported resource type: %s", resource)
 	}
 }
 
-// matchesNamespace checks if a namespace matches the permission namespace
+// matchesPermission checks if an unexpired permission entry covers the namespace, resource and verb of a check
+func matchesPermission(perm Permission, check PermissionCheck) bool {
+	return !perm.Expired(time.Now()) &&
+		matchesNamespace(perm.Namespace, check.Namespace) &&
+		matchesNamespaceSelector(perm.NamespaceSelector, check.NamespaceLabels) &&
+		contains(perm.Resources, check.Resource) &&
+		contains(perm.Verbs, check.Verb)
+}
+
+// matchesNamespace checks if a namespace matches the permission namespace,
+// which is an exact name, "*" or a glob pattern such as "team-a-*"
 func matchesNamespace(permNamespace, requestNamespace string) bool {
 	if permNamespace == "*" {
 		return true
 	}
-	return permNamespace == requestNamespace
+	if !isNamespacePattern(permNamespace) {
+		return permNamespace == requestNamespace
+	}
+
+	// Malformed patterns match nothing
+	matched, err := path.Match(permNamespace, requestNamespace)
+	return err == nil && matched
+}
+
+// matchesResourceName checks if a resource name matches any of the names or glob
+// patterns of a permission entry. An entry without names matches every resource.
+func matchesResourceName(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		// Malformed patterns match nothing
+		if matched, err := path.Match(pattern, name); pattern == name || (err == nil && matched) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNamespacePattern checks if a permission namespace is a glob pattern rather than a name
+func isNamespacePattern(namespace string) bool {
+	return strings.ContainsAny(namespace, "*?[")
+}
+
+// matchesNamespaceSelector checks if namespace labels match a permission's namespace
+// selector. Without a selector every namespace matches; with one, unknown labels
+// and malformed selectors match nothing.
+func matchesNamespaceSelector(namespaceSelector string, namespaceLabels map[string]string) bool {
+	if namespaceSelector == "" {
+		return true
+	}
+	if namespaceLabels == nil {
+		return false
+	}
+
+	selector, err := labels.Parse(namespaceSelector)
+	return err == nil && selector.Matches(labels.Set(namespaceLabels))
 }
 
 // contains checks if a slice contains a string
@@ -120,7 +451,8 @@ func contains(slice []string, item string) bool {
 	return false
 }
 
-// ValidateAndGetNamespaces returns list of namespaces user has access to
+// ValidateAndGetNamespaces returns list of namespaces user has access to. Namespaces
+// a deny rule covers in full are left out.
 func (v *Validator) ValidateAndGetNamespaces(ctx context.Context, userID int64) ([]string, error) {
 	// Bootstrap admins can access all namespaces
 	if v.manager.IsBootstrapAdmin(userID) {
@@ -136,14 +468,14 @@ func (v *Validator) ValidateAndGetNamespaces(ctx context.Context, userID int64)
 		return namespaces, nil
 	}
 
-	// Get user's permissions
-	permission, err := v.manager.GetUserPermission(ctx, userID)
+	// Get the user's own and team permissions
+	access, err := v.manager.GetAccess(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Admin role can access all namespaces
-	if permission.Spec.Role == "admin" {
+	// Admin role can access all namespaces, except those its deny rules cover
+	if access.Role == "admin" {
 		nsList, err := v.k8sClient.ListNamespaces(ctx)
 		if err != nil {
 			return nil, err
@@ -153,34 +485,96 @@ func (v *Validator) ValidateAndGetNamespaces(ctx context.Context, userID int64)
 		for _, ns := range nsList.Items {
 			namespaces = append(namespaces, ns.Name)
 		}
-		return namespaces, nil
+		return v.withoutDeniedNamespaces(ctx, namespaces, access.Deny)
 	}
 
-	// Collect unique namespaces from permissions
+	// Collect unique namespaces from unexpired permissions. Exact names are taken
+	// as they are; patterns and namespace selectors are resolved against the
+	// namespaces in the cluster.
 	nsSet := make(map[string]bool)
-	for _, perm := range permission.Spec.Permissions {
-		if perm.Namespace == "*" {
-			// Wildcard, return all namespaces
-			nsList, err := v.k8sClient.ListNamespaces(ctx)
-			if err != nil {
-				return nil, err
-			}
+	resolve := []Permission{}
+	now := time.Now()
+	for _, perm := range access.Permissions {
+		if perm.Expired(now) {
+			continue
+		}
+		if isNamespacePattern(perm.Namespace) || perm.NamespaceSelector != "" {
+			resolve = append(resolve, perm)
+			continue
+		}
+		nsSet[perm.Namespace] = true
+	}
+
+	if len(resolve) > 0 {
+		nsList, err := v.k8sClient.ListNamespaces(ctx)
+		if err != nil {
+			return nil, err
+		}
 
-			namespaces := []string{}
-			for _, ns := range nsList.Items {
-				namespaces = append(namespaces, ns.Name)
+		for _, ns := range nsList.Items {
+			for _, perm := range resolve {
+				if matchesNamespace(perm.Namespace, ns.Name) && matchesNamespaceSelector(perm.NamespaceSelector, namespaceLabels(ns.Labels)) {
+					nsSet[ns.Name] = true
+					break
+				}
 			}
-			return namespaces, nil
 		}
-		nsSet[perm.Namespace] = true
 	}
 
 	namespaces := []string{}
 	for ns := range nsSet {
 		namespaces = append(namespaces, ns)
 	}
+	sort.Strings(namespaces)
 
-	return namespaces, nil
+	return v.withoutDeniedNamespaces(ctx, namespaces, access.Deny)
+}
+
+// withoutDeniedNamespaces removes the namespaces that a deny rule covers in full,
+// looking up namespace labels only if a deny rule has a namespace selector
+func (v *Validator) withoutDeniedNamespaces(ctx context.Context, namespaces []string, deny []Permission) ([]string, error) {
+	if len(deny) == 0 {
+		return namespaces, nil
+	}
+
+	nsLabels := map[string]map[string]string{}
+	if hasNamespaceSelector(&Access{Deny: deny}) {
+		nsList, err := v.k8sClient.ListNamespaces(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range nsList.Items {
+			nsLabels[ns.Name] = namespaceLabels(ns.Labels)
+		}
+	}
+
+	kept := []string{}
+	for _, ns := range namespaces {
+		if !namespaceDenied(deny, ns, nsLabels[ns]) {
+			kept = append(kept, ns)
+		}
+	}
+	return kept, nil
+}
+
+// namespaceDenied checks if a deny rule covers every verb on every resource in a
+// namespace. Rules with names or a selector only cover some objects.
+func namespaceDenied(deny []Permission, namespace string, nsLabels map[string]string) bool {
+	check := PermissionCheck{Namespace: namespace, NamespaceLabels: nsLabels, Resource: "*", Verb: "*"}
+	for _, rule := range deny {
+		if len(rule.ResourceNames) == 0 && rule.Selector == "" && matchesPermission(rule, check) {
+			return true
+		}
+	}
+	return false
+}
+
+// namespaceLabels returns the labels of a namespace, never nil
+func namespaceLabels(nsLabels map[string]string) map[string]string {
+	if nsLabels == nil {
+		return map[string]string{}
+	}
+	return nsLabels
 }
 
 // FormatPermissionDenied formats a permission denied message
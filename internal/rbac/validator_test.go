@@ -2,6 +2,9 @@ package rbac
 
 import (
 	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestMatchesNamespace(t *testing.T) {
@@ -15,6 +18,12 @@ func TestMatchesNamespace(t *testing.T) {
 		{"production", "production", true},
 		{"production", "staging", false},
 		{"staging", "production", false},
+		{"team-a-*", "team-a-api", true},
+		{"team-a-*", "team-b-api", false},
+		{"preview-pr-????", "preview-pr-1234", true},
+		{"preview-pr-????", "preview-pr-12345", false},
+		{"preview-pr-[0-9]*", "preview-pr-42", true},
+		{"team-[a", "team-[a", false},
 	}
 
 	for _, tt := range tests {
@@ -117,3 +126,300 @@ func TestPermissionCheck_Structure(t *testing.T) {
 		t.Error("Selector mismatch")
 	}
 }
+
+func TestSelectorSetFor(t *testing.T) {
+	permissions := []Permission{
+		{Namespace: "production", Resources: []string{"pods"}, Verbs: []string{"list", "logs"}, Selector: "app=frontend"},
+		{Namespace: "production", Resources: []string{"pods"}, Verbs: []string{"list"}, Selector: "app=admin-ui"},
+		{Namespace: "staging", Resources: []string{"*"}, Verbs: []string{"*"}},
+	}
+
+	frontend := map[string]string{"app": "frontend"}
+	adminUI := map[string]string{"app": "admin-ui"}
+	backend := map[string]string{"app": "backend"}
+
+	tests := []struct {
+		description string
+		check       PermissionCheck
+		labels      map[string]string
+		expected    bool
+	}{
+		{"First selector matches", PermissionCheck{Namespace: "production", Resource: "pods", Verb: "list"}, frontend, true},
+		{"Second selector matches", PermissionCheck{Namespace: "production", Resource: "pods", Verb: "list"}, adminUI, true},
+		{"No selector matches", PermissionCheck{Namespace: "production", Resource: "pods", Verb: "list"}, backend, false},
+		{"Selector only applies to its verbs", PermissionCheck{Namespace: "production", Resource: "pods", Verb: "logs"}, adminUI, false},
+		{"Unrestricted entry", PermissionCheck{Namespace: "staging", Resource: "pods", Verb: "list"}, backend, true},
+		{"No entry for namespace", PermissionCheck{Namespace: "dev", Resource: "pods", Verb: "list"}, frontend, false},
+	}
+
+	for _, tt := range tests {
+		set, err := selectorSetFor(permissions, tt.check)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.description, err)
+			continue
+		}
+
+		if result := set.Matches("web-0", tt.labels); result != tt.expected {
+			t.Errorf("%s: Matches(%v) = %v, expected %v", tt.description, tt.labels, result, tt.expected)
+		}
+	}
+}
+
+func TestSelectorSetFor_InvalidSelector(t *testing.T) {
+	permissions := []Permission{
+		{Namespace: "production", Resources: []string{"pods"}, Verbs: []string{"list"}, Selector: "app in (frontend"},
+	}
+
+	_, err := selectorSetFor(permissions, PermissionCheck{Namespace: "production", Resource: "pods", Verb: "list"})
+	if err == nil {
+		t.Error("Expected error for invalid selector")
+	}
+}
+
+func TestMatchesPermission_Expiry(t *testing.T) {
+	past := metav1.NewTime(time.Now().Add(-time.Minute))
+	future := metav1.NewTime(time.Now().Add(time.Hour))
+	check := PermissionCheck{Namespace: "staging", Resource: "deployments", Verb: "restart"}
+
+	tests := []struct {
+		description string
+		expiresAt   *metav1.Time
+		expected    bool
+	}{
+		{"Permanent grant", nil, true},
+		{"Grant not yet expired", &future, true},
+		{"Expired grant is ignored", &past, false},
+	}
+
+	for _, tt := range tests {
+		perm := Permission{Namespace: "staging", Resources: []string{"deployments"}, Verbs: []string{"restart"}, ExpiresAt: tt.expiresAt}
+		if result := matchesPermission(perm, check); result != tt.expected {
+			t.Errorf("%s: matchesPermission() = %v, expected %v", tt.description, result, tt.expected)
+		}
+	}
+
+	// Expired entries do not widen list results either
+	permissions := []Permission{
+		{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"list"}, ExpiresAt: &past},
+	}
+	set, err := selectorSetFor(permissions, PermissionCheck{Namespace: "staging", Resource: "pods", Verb: "list"})
+	if err != nil || set.Allowed {
+		t.Errorf("selectorSetFor() = %+v, %v, expected expired entry to be ignored", set, err)
+	}
+}
+
+func TestApplyDenyRules(t *testing.T) {
+	permissions := []Permission{
+		{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"*"}},
+	}
+	deny := []Permission{
+		{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"logs"}, Selector: "sensitive=true"},
+		{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"trigger"}},
+	}
+
+	sensitive := map[string]string{"app": "vault", "sensitive": "true"}
+	regular := map[string]string{"app": "api"}
+
+	tests := []struct {
+		description string
+		verb        string
+		labels      map[string]string
+		expected    bool
+	}{
+		{"Deny selector hides matching pods", "logs", sensitive, false},
+		{"Deny selector keeps other pods", "logs", regular, true},
+		{"Deny rule only applies to its verbs", "list", sensitive, true},
+		{"Deny rule without selector hides everything", "trigger", regular, false},
+	}
+
+	for _, tt := range tests {
+		check := PermissionCheck{Namespace: "staging", Resource: "pods", Verb: tt.verb}
+		set, err := selectorSetFor(permissions, check)
+		if err == nil {
+			set, err = applyDenyRules(set, deny, check)
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.description, err)
+			continue
+		}
+
+		if result := set.Matches("web-0", tt.labels); result != tt.expected {
+			t.Errorf("%s: Matches(%v) = %v, expected %v", tt.description, tt.labels, result, tt.expected)
+		}
+	}
+}
+
+func TestApplyDenyRules_InvalidSelector(t *testing.T) {
+	deny := []Permission{
+		{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"logs"}, Selector: "app in (vault"},
+	}
+
+	_, err := applyDenyRules(SelectorSet{Allowed: true, Unrestricted: true}, deny, PermissionCheck{Namespace: "staging", Resource: "pods", Verb: "logs"})
+	if err == nil {
+		t.Error("Expected error for invalid deny selector")
+	}
+}
+
+func TestMatchesResourceName(t *testing.T) {
+	tests := []struct {
+		description string
+		patterns    []string
+		name        string
+		expected    bool
+	}{
+		{"No names matches everything", nil, "api", true},
+		{"Exact name", []string{"api"}, "api", true},
+		{"Exact name does not match prefix", []string{"api"}, "api-worker", false},
+		{"Glob pattern", []string{"api-*"}, "api-worker", true},
+		{"Glob does not match other names", []string{"api-*"}, "web", false},
+		{"Any of several patterns", []string{"web", "api-*"}, "web", true},
+		{"Malformed pattern", []string{"api-["}, "api-x", false},
+	}
+
+	for _, tt := range tests {
+		if result := matchesResourceName(tt.patterns, tt.name); result != tt.expected {
+			t.Errorf("%s: matchesResourceName(%v, %q) = %v, expected %v", tt.description, tt.patterns, tt.name, result, tt.expected)
+		}
+	}
+}
+
+func TestSelectorSetFor_ResourceNames(t *testing.T) {
+	permissions := []Permission{
+		{Namespace: "production", Resources: []string{"deployments"}, Verbs: []string{"list"}, ResourceNames: []string{"api-*"}},
+		{Namespace: "production", Resources: []string{"deployments"}, Verbs: []string{"list"}, ResourceNames: []string{"web"}, Selector: "tier=frontend"},
+	}
+	deny := []Permission{
+		{Namespace: "production", Resources: []string{"deployments"}, Verbs: []string{"list"}, ResourceNames: []string{"api-internal"}},
+	}
+
+	frontend := map[string]string{"tier": "frontend"}
+	backend := map[string]string{"tier": "backend"}
+
+	tests := []struct {
+		description string
+		name        string
+		labels      map[string]string
+		expected    bool
+	}{
+		{"Name matches pattern", "api-gateway", backend, true},
+		{"Name matches no pattern", "worker", backend, false},
+		{"Name and selector match", "web", frontend, true},
+		{"Name matches but selector does not", "web", backend, false},
+		{"Deny rule hides named object", "api-internal", backend, false},
+	}
+
+	check := PermissionCheck{Namespace: "production", Resource: "deployments", Verb: "list"}
+	set, err := selectorSetFor(permissions, check)
+	if err == nil {
+		set, err = applyDenyRules(set, deny, check)
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tt := range tests {
+		if result := set.Matches(tt.name, tt.labels); result != tt.expected {
+			t.Errorf("%s: Matches(%q, %v) = %v, expected %v", tt.description, tt.name, tt.labels, result, tt.expected)
+		}
+	}
+}
+
+func TestDescribeRule(t *testing.T) {
+	rule := DescribeRule(Permission{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"logs"}, Selector: "sensitive=true"})
+	expected := "logs on pods in namespace 'staging' with selector sensitive=true"
+	if rule != expected {
+		t.Errorf("DescribeRule() = %q, expected %q", rule, expected)
+	}
+
+	rule = DescribeRule(Permission{Namespace: "production", Resources: []string{"deployments"}, Verbs: []string{"restart"}, ResourceNames: []string{"api-*"}})
+	expected = "restart on deployments in namespace 'production' named api-*"
+	if rule != expected {
+		t.Errorf("DescribeRule() = %q, expected %q", rule, expected)
+	}
+}
+
+func TestMatchesNamespaceSelector(t *testing.T) {
+	payments := map[string]string{"team": "payments", "env": "prod"}
+
+	tests := []struct {
+		description string
+		selector    string
+		labels      map[string]string
+		expected    bool
+	}{
+		{"No selector matches everything", "", nil, true},
+		{"Matching labels", "team=payments", payments, true},
+		{"Set-based selector", "env in (prod, staging),team=payments", payments, true},
+		{"Different labels", "team=search", payments, false},
+		{"Unknown labels", "team=payments", nil, false},
+		{"Malformed selector", "team in (payments", payments, false},
+	}
+
+	for _, tt := range tests {
+		if result := matchesNamespaceSelector(tt.selector, tt.labels); result != tt.expected {
+			t.Errorf("%s: matchesNamespaceSelector(%q) = %v, expected %v", tt.description, tt.selector, result, tt.expected)
+		}
+	}
+}
+
+func TestMatchesPermission_NamespaceSelector(t *testing.T) {
+	perm := Permission{Namespace: "team-*", NamespaceSelector: "team=payments", Resources: []string{"pods"}, Verbs: []string{"list"}}
+
+	tests := []struct {
+		description string
+		namespace   string
+		labels      map[string]string
+		expected    bool
+	}{
+		{"Glob and labels match", "team-payments", map[string]string{"team": "payments"}, true},
+		{"Labels do not match", "team-search", map[string]string{"team": "search"}, false},
+		{"Glob does not match", "payments", map[string]string{"team": "payments"}, false},
+		{"Labels not looked up", "team-payments", nil, false},
+	}
+
+	for _, tt := range tests {
+		check := PermissionCheck{Namespace: tt.namespace, Resource: "pods", Verb: "list", NamespaceLabels: tt.labels}
+		if result := matchesPermission(perm, check); result != tt.expected {
+			t.Errorf("%s: matchesPermission() = %v, expected %v", tt.description, result, tt.expected)
+		}
+	}
+}
+
+func TestHasNamespaceSelector(t *testing.T) {
+	access := &Access{Permissions: []Permission{{Namespace: "team-*"}}}
+	if hasNamespaceSelector(access) {
+		t.Error("Globs alone do not need namespace labels")
+	}
+
+	access.Deny = []Permission{{Namespace: "*", NamespaceSelector: "pii=true"}}
+	if !hasNamespaceSelector(access) {
+		t.Error("Expected deny rule with namespace selector to need namespace labels")
+	}
+}
+
+func TestNamespaceDenied(t *testing.T) {
+	deny := []Permission{
+		{Namespace: "kube-*", Resources: []string{"*"}, Verbs: []string{"*"}},
+		{Namespace: "*", NamespaceSelector: "pii=true", Resources: []string{"*"}, Verbs: []string{"*"}},
+		{Namespace: "staging", Resources: []string{"pods"}, Verbs: []string{"*"}},
+		{Namespace: "production", Resources: []string{"*"}, Verbs: []string{"*"}, Selector: "sensitive=true"},
+	}
+
+	tests := []struct {
+		namespace string
+		labels    map[string]string
+		expected  bool
+	}{
+		{"kube-system", nil, true},
+		{"payments", map[string]string{"pii": "true"}, true},
+		{"payments", map[string]string{"pii": "false"}, false},
+		{"staging", nil, false},
+		{"production", nil, false},
+	}
+
+	for _, tt := range tests {
+		if result := namespaceDenied(deny, tt.namespace, tt.labels); result != tt.expected {
+			t.Errorf("namespaceDenied(%q, %v) = %v, expected %v", tt.namespace, tt.labels, result, tt.expected)
+		}
+	}
+}